@@ -0,0 +1,127 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/renstrom/dedent"
+	"github.com/spf13/cobra"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+)
+
+// Values used to print help command.
+var (
+	authCmdUse       = "auth"
+	authCmdShortDesc = "Manage the stored WSO2 credentials"
+	authCmdLongDesc  = dedent.Dedent(`
+		This command can be used to view the status of the tokens obtained
+		using 'wum-uc init' and to remove them.`)
+
+	authStatusCmdShortDesc = "Show the status of the stored tokens"
+	authStatusCmdLongDesc  = dedent.Dedent(`
+		This command will show the user and the expiry time of the stored
+		access token. Access tokens are renewed automatically before they
+		expire, as long as the refresh token is valid.`)
+
+	authLogoutCmdShortDesc = "Revoke and remove the stored tokens"
+	authLogoutCmdLongDesc  = dedent.Dedent(`
+		This command will revoke the stored access token and refresh token
+		and remove them from the credential store. Run 'wum-uc init' to log
+		in again.`)
+)
+
+// authCmd represents the auth command.
+var authCmd = &cobra.Command{
+	Use:   authCmdUse,
+	Short: authCmdShortDesc,
+	Long:  authCmdLongDesc,
+}
+
+// authStatusCmd represents the auth status command.
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: authStatusCmdShortDesc,
+	Long:  authStatusCmdLongDesc,
+	Run:   initializeAuthStatusCommand,
+}
+
+// authLogoutCmd represents the auth logout command.
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: authLogoutCmdShortDesc,
+	Long:  authLogoutCmdLongDesc,
+	Run:   initializeAuthLogoutCommand,
+}
+
+// This function will be called first and this will add flags to the command.
+func init() {
+	RootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+
+	authCmd.PersistentFlags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
+	authCmd.PersistentFlags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
+}
+
+// This function will be called when the auth status command is called.
+func initializeAuthStatusCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[auth status] called")
+	wumucConfig := util.GetWUMUCConfigs()
+	if len(wumucConfig.RefreshToken) == 0 && len(wumucConfig.AccessToken) == 0 && len(wumucConfig.ClientID) == 0 {
+		util.PrintInfo(fmt.Sprintf("You are not logged in, %s.", constant.RUN_WUMUC_INIT_TO_CONTINUE_MSG))
+		return
+	}
+	switch {
+	case len(wumucConfig.ClientID) > 0:
+		fmt.Fprintf(os.Stdout, "Logged in as: client %v\n", wumucConfig.ClientID)
+	case len(wumucConfig.Username) > 0:
+		fmt.Fprintf(os.Stdout, "Logged in as: %v\n", wumucConfig.Username)
+	}
+	if profile := wumucConfig.GetProfile(); len(profile) > 0 {
+		fmt.Fprintf(os.Stdout, "Profile: %v\n", profile)
+	}
+	if wumucConfig.AreCredentialsFromEnvironment() {
+		fmt.Fprintln(os.Stdout, "Credentials: environment variables")
+	}
+	fmt.Fprintf(os.Stdout, "Token URL: %v\n", wumucConfig.TokenURL)
+	if len(wumucConfig.AccessToken) == 0 {
+		fmt.Fprintln(os.Stdout, "Access token: not obtained yet")
+		return
+	}
+	expiryTime, found := wumucConfig.GetAccessTokenExpiryTime()
+	if !found {
+		fmt.Fprintln(os.Stdout, "Access token expires at: unknown")
+		return
+	}
+	remaining := time.Until(expiryTime)
+	if remaining <= 0 {
+		fmt.Fprintf(os.Stdout, "Access token expired at: %v (it will be renewed with the next request)\n",
+			expiryTime.Local().Format(time.RFC1123))
+		return
+	}
+	fmt.Fprintf(os.Stdout, "Access token expires at: %v (in %v)\n", expiryTime.Local().Format(time.RFC1123),
+		remaining.Truncate(time.Second))
+}
+
+// This function will be called when the auth logout command is called.
+func initializeAuthLogoutCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[auth logout] called")
+	util.Logout()
+	fmt.Fprint(os.Stderr, constant.DONE_MSG)
+}
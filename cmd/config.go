@@ -0,0 +1,331 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/renstrom/dedent"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Name used for the top level values of the config.yaml when listing the profiles.
+const defaultProfileName = "default"
+
+// Value shown instead of the secrets when the configuration is listed.
+const maskedConfigValue = "********"
+
+// Used to show the types, environment variables and descriptions of the keys.
+var isLongListingEnabled = false
+
+// Values used to print help command.
+var (
+	configCmdUse       = "config"
+	configCmdShortDesc = "Manage the wum-uc configuration"
+	configCmdLongDesc  = dedent.Dedent(`
+		This command can be used to view and change the configuration of
+		wum-uc. Every key can be overridden using an environment variable
+		with the WUMUC_ prefix, ie. HTTP_CLIENT.MAX_RETRIES can be
+		overridden using WUMUC_HTTP_CLIENT_MAX_RETRIES.`)
+
+	configGetCmdShortDesc = "Show the value of a key"
+	configGetCmdLongDesc  = dedent.Dedent(`
+		This command will show the current value of the given key. Keys
+		are case insensitive.`)
+
+	configSetCmdShortDesc = "Change the value of a key"
+	configSetCmdLongDesc  = dedent.Dedent(`
+		This command will validate the given value and write it to the
+		config file. Values of lists are given as separate arguments and
+		values of maps are given as key=value arguments.
+
+		wum-uc config set HASH_ALGORITHM sha256
+		wum-uc config set RESOURCE_FILES.SKIP README.txt NOTES.txt
+		wum-uc config set MAX_CLASS_VERSIONS 4.4.0=52 5.0.0=52`)
+
+	configListCmdShortDesc = "List the keys and their values"
+	configListCmdLongDesc  = dedent.Dedent(`
+		This command will list the keys which can be configured along
+		with their current values and where the values are taken from.`)
+
+	configValidateCmdShortDesc = "Validate the configuration"
+	configValidateCmdLongDesc  = dedent.Dedent(`
+		This command will validate the current values of all the keys,
+		including the values given using environment variables.`)
+
+	configPathCmdShortDesc = "Show the locations of the config files"
+	configPathCmdLongDesc  = dedent.Dedent(`
+		This command will show the config.yaml in the wum-uc home and the
+		config file from which the other settings are read.`)
+
+	configProfilesCmdShortDesc = "List the configured profiles"
+	configProfilesCmdLongDesc  = dedent.Dedent(`
+		This command will list the profiles in the config.yaml along with
+		their server URLs. The active profile is marked with '*'. A profile
+		can be selected using the --profile flag or the WUMUC_PROFILE
+		environment variable.`)
+)
+
+// configCmd represents the config command.
+var configCmd = &cobra.Command{
+	Use:   configCmdUse,
+	Short: configCmdShortDesc,
+	Long:  configCmdLongDesc,
+}
+
+// configGetCmd represents the config get command.
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: configGetCmdShortDesc,
+	Long:  configGetCmdLongDesc,
+	Args:  cobra.ExactArgs(1),
+	Run:   initializeConfigGetCommand,
+}
+
+// configSetCmd represents the config set command.
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>...",
+	Short: configSetCmdShortDesc,
+	Long:  configSetCmdLongDesc,
+	Args:  cobra.MinimumNArgs(1),
+	Run:   initializeConfigSetCommand,
+}
+
+// configListCmd represents the config list command.
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: configListCmdShortDesc,
+	Long:  configListCmdLongDesc,
+	Args:  cobra.NoArgs,
+	Run:   initializeConfigListCommand,
+}
+
+// configValidateCmd represents the config validate command.
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: configValidateCmdShortDesc,
+	Long:  configValidateCmdLongDesc,
+	Args:  cobra.NoArgs,
+	Run:   initializeConfigValidateCommand,
+}
+
+// configPathCmd represents the config path command.
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: configPathCmdShortDesc,
+	Long:  configPathCmdLongDesc,
+	Args:  cobra.NoArgs,
+	Run:   initializeConfigPathCommand,
+}
+
+// configProfilesCmd represents the config profiles command.
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: configProfilesCmdShortDesc,
+	Long:  configProfilesCmdLongDesc,
+	Run:   initializeConfigProfilesCommand,
+}
+
+// This function will be called first and this will add flags to the command.
+func init() {
+	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configProfilesCmd)
+
+	configCmd.PersistentFlags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs,
+		"Enable debug logs")
+	configCmd.PersistentFlags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs,
+		"Enable trace logs")
+	configListCmd.Flags().BoolVarP(&isLongListingEnabled, "long", "l", false, "Show the types, environment "+
+		"variables and descriptions of the keys")
+}
+
+// This function will be called when the config get command is called.
+func initializeConfigGetCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config get] called")
+	key, err := util.GetConfigKey(args[0])
+	if err != nil {
+		util.HandleErrorAndExit(err)
+	}
+	value, source := key.GetValue()
+	logger.Debug(fmt.Sprintf("Value of %s is taken from: %s", key.Name, source))
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Map:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while formatting the value of '%s'.", key.Name))
+		}
+		fmt.Fprint(os.Stdout, string(data))
+	case reflect.Invalid:
+	default:
+		fmt.Fprintln(os.Stdout, value)
+	}
+}
+
+// This function will be called when the config set command is called.
+func initializeConfigSetCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config set] called")
+	key, err := util.GetConfigKey(args[0])
+	if err != nil {
+		util.HandleErrorAndExit(err)
+	}
+	value, err := key.ParseValue(args[1:])
+	if err != nil {
+		util.HandleErrorAndExit(err)
+	}
+	configFilePath, err := util.SetConfigValue(key, value)
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while setting '%s'.", key.Name))
+	}
+	util.PrintInfo(fmt.Sprintf("'%s' is set in '%s'.", key.Name, configFilePath))
+	if _, exists := os.LookupEnv(key.GetEnvName()); exists {
+		util.PrintWarning(fmt.Sprintf("'%s' is overridden by the %s environment variable.", key.Name,
+			key.GetEnvName()))
+	}
+}
+
+// This function will be called when the config list command is called.
+func initializeConfigListCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config list] called")
+	configTable := tablewriter.NewWriter(os.Stdout)
+	configTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	header := []string{"Key", "Value", "Source"}
+	if isLongListingEnabled {
+		header = append(header, "Type", "Environment Variable", "Description")
+	}
+	configTable.SetHeader(header)
+	for _, key := range util.GetConfigKeys() {
+		value, source := key.GetValue()
+		row := []string{key.Name, formatConfigValue(&key, value), source}
+		if isLongListingEnabled {
+			row = append(row, key.Type, key.GetEnvName(), key.Description)
+		}
+		configTable.Append(row)
+	}
+	configTable.Render()
+}
+
+// This function will be called when the config validate command is called.
+func initializeConfigValidateCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config validate] called")
+	errs := util.ValidateConfig()
+	if len(errs) == 0 {
+		util.PrintInfo("Configuration is valid.")
+		return
+	}
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "\t%v\n", err)
+	}
+	util.HandleErrorAndExit(errors.New(fmt.Sprintf("%d invalid value(s) found in the configuration", len(errs))))
+}
+
+// This function will be called when the config path command is called.
+func initializeConfigPathCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config path] called")
+	fmt.Fprintf(os.Stdout, "wum-uc home: %v\n", viper.GetString(constant.WUM_UC_HOME))
+	fmt.Fprintf(os.Stdout, "Config file: %v\n", util.GetWUMUCConfigFilePath())
+	if configFileUsed := viper.ConfigFileUsed(); len(configFileUsed) > 0 &&
+		configFileUsed != util.GetWUMUCConfigFilePath() {
+		fmt.Fprintf(os.Stdout, "Settings file: %v\n", configFileUsed)
+	}
+}
+
+// This function returns the given value of the key in a single line, so that it can be shown in a table. Secrets are
+// masked and structured values are not shown.
+func formatConfigValue(key *util.ConfigKey, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if key.Type == constant.CONFIG_TYPE_STRUCTURED {
+		return fmt.Sprintf("(run 'wum-uc config get %s')", key.Name)
+	}
+	if key.IsSecret && len(fmt.Sprint(value)) > 0 {
+		return maskedConfigValue
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Slice:
+		values := make([]string, 0, reflectValue.Len())
+		for i := 0; i < reflectValue.Len(); i++ {
+			values = append(values, fmt.Sprint(reflectValue.Index(i).Interface()))
+		}
+		return strings.Join(values, ", ")
+	case reflect.Map:
+		values := make([]string, 0, reflectValue.Len())
+		for _, mapKey := range reflectValue.MapKeys() {
+			values = append(values, fmt.Sprintf("%v=%v", mapKey.Interface(), reflectValue.MapIndex(mapKey).Interface()))
+		}
+		sort.Strings(values)
+		return strings.Join(values, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// This function will be called when the config profiles command is called.
+func initializeConfigProfilesCommand(cmd *cobra.Command, args []string) {
+	logger.Debug("[config profiles] called")
+	storedConfig, err := util.GetStoredWUMUCConfigs()
+	if err != nil {
+		util.HandleErrorAndExit(err, "Error occurred while reading the config.yaml.")
+	}
+	activeProfile := util.GetWUMUCConfigs().GetProfile()
+
+	profileTable := tablewriter.NewWriter(os.Stdout)
+	profileTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	profileTable.SetHeader([]string{"", "Profile", "Server URL", "User"})
+	profileTable.Append(getProfileRow(defaultProfileName, len(activeProfile) == 0, storedConfig.ServerURL,
+		storedConfig.Username, storedConfig.ClientID))
+	for _, name := range storedConfig.GetProfileNames() {
+		profile := storedConfig.Profiles[name]
+		if profile == nil {
+			profile = &util.WUMUCProfile{}
+		}
+		// Profiles without a server URL use the top level server URL
+		serverURL := profile.ServerURL
+		if len(serverURL) == 0 {
+			serverURL = storedConfig.ServerURL
+		}
+		profileTable.Append(getProfileRow(name, name == activeProfile, serverURL, profile.Username,
+			profile.ClientID))
+	}
+	profileTable.Render()
+}
+
+// This function returns the row which represents the given profile in the profile table.
+func getProfileRow(name string, isActive bool, serverURL, username, clientID string) []string {
+	marker := ""
+	if isActive {
+		marker = "*"
+	}
+	user := username
+	if len(clientID) > 0 {
+		user = "client " + clientID
+	}
+	return []string{marker, name, serverURL, user}
+}
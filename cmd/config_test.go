@@ -0,0 +1,77 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestConfigFileFlag(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("svn is mocked using a shell script")
+	}
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// config.yaml in the current directory should not be read when a config file is given
+	workDirectory := filepath.Join(tempDir, "work")
+	settingsFile := filepath.Join(tempDir, "settings.yaml")
+	writeTestFiles(t, tempDir, map[string]string{
+		"bin/" + constant.SVN_COMMAND: "#!/bin/sh\nexit 0\n",
+		"work/config.yaml":            "HASH_ALGORITHM: sha256\n",
+		"settings.yaml":               "HASH_ALGORITHM: md5\n",
+		"home/" + constant.WUMUC_CONFIG_FILE: "serverurl: https://wum.example.com\n" +
+			"tokenurl: https://wum.example.com/token\nversionurl: https://wum.example.com\n" +
+			"appkey: a2V5OnNlY3JldA==\n",
+	})
+	env := []string{
+		constant.WUM_UC_HOME + "=" + filepath.Join(tempDir, "home"),
+		"PATH=" + filepath.Join(tempDir, "bin") + string(os.PathListSeparator) + os.Getenv("PATH"),
+	}
+
+	output, err := runTestCommand(t, workDirectory, env, "", "--config", settingsFile, "config", "get",
+		constant.HASH_ALGORITHM)
+	if err != nil || strings.TrimSpace(output) != constant.HASH_ALGORITHM_MD5 {
+		t.Errorf("Test failed, value not read from the given config file: %s, error: %v", output, err)
+	}
+	output, err = runTestCommand(t, workDirectory, env, "", "--config", settingsFile, "config", "path")
+	if err != nil || !strings.Contains(output, "Settings file: "+settingsFile) {
+		t.Errorf("Test failed, given config file not shown: %s, error: %v", output, err)
+	}
+
+	// Value should be written to the given config file
+	if _, err = runTestCommand(t, workDirectory, env, "", "--config", settingsFile, "config", "set",
+		constant.HASH_ALGORITHM, constant.HASH_ALGORITHM_SHA256); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(settingsFile)
+	if err != nil || !strings.Contains(string(data), constant.HASH_ALGORITHM_SHA256) {
+		t.Errorf("Test failed, value not written to the given config file: %s, error: %v", string(data), err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(workDirectory, "config.yaml"))
+	if err != nil || string(data) != "HASH_ALGORITHM: sha256\n" {
+		t.Errorf("Test failed, config.yaml in the current directory modified: %s, error: %v", string(data), err)
+	}
+}
@@ -16,8 +16,6 @@ package cmd
 
 import (
 	"archive/zip"
-	"crypto/md5"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -47,20 +45,26 @@ import (
 
 // This struct is used to store file/directory information.
 type data struct {
-	name         string
-	isDir        bool
-	relativePath string
-	md5          string
+	name               string
+	isDir              bool
+	relativePath       string
+	checksum           string
+	bundleSymbolicName string
+	bundleVersion      string
 }
 
 // This struct used to store directory structure of the distribution.
 type node struct {
-	name             string
-	isDir            bool
-	relativeLocation string
-	parent           *node
-	childNodes       map[string]*node
-	md5Hash          string
+	name               string
+	isDir              bool
+	relativeLocation   string
+	parent             *node
+	childNodes         map[string]*node
+	checksum           string
+	bundleSymbolicName string
+	bundleVersion      string
+	// CRC32 checksums of the entries of jar files. Used to report class level changes of modified jars
+	jarEntries map[string]uint32
 }
 
 // This struct is used for resuming the update creation using `wum-uc create -- continue`
@@ -101,6 +105,8 @@ var createCmd = &cobra.Command{
 }
 
 var isContinueEnabled = false
+var isWizardEnabled = false
+var isOfflineEnabled = false
 
 // This function will be called first and this will add flags to the command.
 func init() {
@@ -109,9 +115,24 @@ func init() {
 	createCmd.Flags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
 	createCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
 	createCmd.Flags().BoolVar(&isContinueEnabled, "continue", false, "Continue resumed update creation")
+	createCmd.Flags().BoolVarP(&isWizardEnabled, "wizard", "w", false, "Prompt for the description, instructions "+
+		"and bug fixes of the update and create the update zip in the same session")
 
-	createCmd.Flags().BoolP("md5", "m", util.CheckMd5Disabled, "Disable checking MD5 sum")
+	createCmd.Flags().BoolP("md5", "m", util.CheckMd5Disabled, "Disable checking checksums")
 	viper.BindPFlag(constant.CHECK_MD5_DISABLED, createCmd.Flags().Lookup("md5"))
+	createCmd.Flags().Bool("deterministic", util.DeterministicZip, "Create a reproducible update zip")
+	viper.BindPFlag(constant.DETERMINISTIC_ZIP, createCmd.Flags().Lookup("deterministic"))
+	createCmd.Flags().Bool("resolve-symlinks", util.ResolveSymlinks, "Replace symbolic links to files in the "+
+		"update directory with the actual files")
+	viper.BindPFlag(constant.RESOLVE_SYMLINKS, createCmd.Flags().Lookup("resolve-symlinks"))
+	createCmd.Flags().BoolVar(&isSecretsAllowed, "allow-secrets", false, "Continue even if secrets are found in "+
+		"the update")
+	createCmd.Flags().StringVar(&secretsAllowlistPath, "secrets-allowlist", "", "Location of the allowlist of "+
+		"known false positives of the secret scan")
+	createCmd.Flags().BoolVar(&isOfflineEnabled, "offline", false, "Find the products affected by the update "+
+		"using the product catalog instead of the WUM servers")
+	createCmd.Flags().String("catalog", "", "Location of the product catalog used in the offline mode")
+	viper.BindPFlag(constant.PRODUCT_CATALOG, createCmd.Flags().Lookup("catalog"))
 }
 
 // This function will be called when the create command is called.
@@ -176,6 +197,8 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 		util.PrintInBold(fmt.Sprintf("Directory created. Please copy updated files to '%s' and rerun 'wum-uc create'", updateDirectoryPath))
 		os.Exit(1)
 	}
+	// Load the project configuration as it overrides the user config for this update
+	loadProjectConfig(updateDirectoryPath)
 	updateRoot := strings.TrimSuffix(updateDirectoryPath, constant.PATH_SEPARATOR)
 	logger.Debug(fmt.Sprintf("updateRoot: %s\n", updateRoot))
 	viper.Set(constant.UPDATE_ROOT, updateRoot)
@@ -186,6 +209,8 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 
 	//2) Process the README.txt file if it exists
 	readMeDataString := processReadMe(updateDirectoryPath, &updateDescriptorV2)
+	// Values pinned in the project configuration take precedence over the values found in the README.txt
+	applyPinnedUpdateDetails(&updateDescriptorV2)
 
 	//3) Check whether the given distribution exists
 	exists, err = util.IsFileExists(distributionPath)
@@ -198,7 +223,7 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 	util.IsZipFile(constant.DISTRIBUTION, distributionPath)
 
 	//4) Set the update name
-	updateName := getUpdateName(&updateDescriptorV2, constant.UPDATE_NAME_PREFIX)
+	updateName := getUpdateName(&updateDescriptorV2, util.GetProjectConfig().GetUpdateNamePrefix())
 	viper.Set(constant.UPDATE_NAME, updateName)
 
 	//5) Validate UpdateDescriptorV2 for basic details of update-descriptor.yaml
@@ -268,6 +293,14 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 		matches = make(map[string]*node)
 		// Find all matching locations for the directory
 		logger.Debug(fmt.Sprintf("DirectoryName: %s", directoryName))
+		// Copy the directory to the destinations pinned in the project configuration without finding matches
+		if destinations, pinned := util.GetProjectConfig().GetDestinations(directoryName); pinned {
+			logger.Debug(fmt.Sprintf("Pinned destinations: %v", destinations))
+			warnMissingDestinations(directoryName, destinations, &rootNode)
+			err := copyToLocations(directoryName, true, destinations, allFilesMap, &rootNode, &updateDescriptorV2)
+			util.HandleErrorAndExit(err)
+			continue
+		}
 		FindMatches(&rootNode, directoryName, true, matches)
 		logger.Debug(fmt.Sprintf("matches: %v", matches))
 
@@ -307,6 +340,14 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 		matches = make(map[string]*node)
 		// Find all matching locations for the file
 		logger.Debug(fmt.Sprintf("FileName: %s", fileName))
+		// Copy the file to the destinations pinned in the project configuration without finding matches
+		if destinations, pinned := util.GetProjectConfig().GetDestinations(fileName); pinned {
+			logger.Debug(fmt.Sprintf("Pinned destinations: %v", destinations))
+			warnMissingDestinations(fileName, destinations, &rootNode)
+			err := copyToLocations(fileName, false, destinations, allFilesMap, &rootNode, &updateDescriptorV2)
+			util.HandleErrorAndExit(err)
+			continue
+		}
 		FindMatches(&rootNode, fileName, false, matches)
 		logger.Debug(fmt.Sprintf("matches: %v", matches))
 
@@ -339,28 +380,29 @@ func createUpdate(updateDirectoryPath, distributionPath string) {
 		}
 	}
 
-	//9) Request the user to add removed files as they can't be identified by comparing.
-removedFilesInputLoop:
-	for {
-		util.PrintInBold(fmt.Sprintf("\nAre the existing files in %s removed from this update? [y"+
-			"/n]: ",
-			distributionName))
-		preference, err := util.GetUserInput()
-		util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
-		userPreference := util.ProcessUserPreference(preference)
-		switch userPreference {
-		case constant.YES:
-			appendRemovedFilesToUpdateDescriptor(&updateDescriptorV2)
-			break removedFilesInputLoop
-		case constant.NO:
-			break removedFilesInputLoop
-		default:
-			util.PrintError("Invalid preference. Enter y for Yes or n for No.")
-		}
+	//9) Request the user to add removed files as they can't be identified by comparing. Removed files pinned in the
+	// project configuration are used without requesting the user
+	if removedFiles := util.GetProjectConfig().RemovedFiles; len(removedFiles) > 0 {
+		util.PrintInfo(fmt.Sprintf("Removed files found in the %s: %v", constant.PROJECT_CONFIG_FILE, removedFiles))
+		updateDescriptorV2.FileChanges.RemovedFiles = append(updateDescriptorV2.FileChanges.RemovedFiles,
+			removedFiles...)
+	} else {
+		requestRemovedFiles(distributionName, &updateDescriptorV2)
 	}
 
 	// Get partial updated file changes
-	partialUpdatedFileResponse := util.GetPartialUpdatedFiles(&updateDescriptorV2)
+	var partialUpdatedFileResponse *util.PartialUpdatedFileResponse
+	if isOfflineEnabled {
+		logger.Debug(fmt.Sprintf("Finding the affected products using the product catalog %s",
+			util.GetProductCatalogPath()))
+		partialUpdatedFileResponse = util.GetPartialUpdatedFilesOffline(&updateDescriptorV2)
+	} else {
+		var err error
+		partialUpdatedFileResponse, err = util.GetPartialUpdatedFiles(&updateDescriptorV2)
+		if err != nil {
+			util.HandleUnableToConnectErrorAndExit(err)
+		}
+	}
 	if partialUpdatedFileResponse.BackwardCompatible {
 		// Create update-descriptor.yaml
 		if len(readMeDataString) != 0 {
@@ -397,8 +439,14 @@ removedFilesInputLoop:
 		updateDescriptorV3.PartiallyApplicableProducts = append(updateDescriptorV3.PartiallyApplicableProducts, *productChanges)
 	}
 
-	// Generate md5sum for the content generated by wum-uc tool
-	updateDescriptorV3.Md5sum = util.GenerateMd5sumForGeneratedContent(&updateDescriptorV3)
+	// Generate the checksum for the content generated by wum-uc tool
+	hashAlgorithm := util.GetHashAlgorithm()
+	checksum := util.GenerateChecksumForGeneratedContent(&updateDescriptorV3, hashAlgorithm)
+	if hashAlgorithm == constant.HASH_ALGORITHM_MD5 {
+		updateDescriptorV3.Md5sum = checksum
+	} else {
+		updateDescriptorV3.Sha256sum = checksum
+	}
 
 	// Set values to compatible products slice for displaying purpose
 	var compatibleProducts []string
@@ -458,6 +506,13 @@ removedFilesInputLoop:
 	util.PrintInBold(fmt.Sprintf("\tPartially applicable products : %v \n", partiallyApplicableProducts))
 	util.PrintInBold(fmt.Sprintf("\tNotify products : %v \n", notifyProducts))
 
+	// Complete the update-descriptor3.yaml and create the update zip in the same session
+	if isWizardEnabled {
+		completeUpdateDescriptorV3(updateDirectoryPath, &updateDescriptorV3)
+		continueResumedUpdateCreation()
+		return
+	}
+
 	util.PrintInBold(fmt.Sprintf("Manually fill the `description`,"+
 		"`instructions` and `bug_fixes` fields for above products in the update-descriptor3."+
 		"yaml located inside %s directory\n", updateDirectoryPath))
@@ -505,13 +560,11 @@ func processReadMe(updateDirectoryPath string, updateDescriptorV2 *util.UpdateDe
 			// Extract details
 			updateDescriptorV2.UpdateNumber = result[2]
 			updateDescriptorV2.PlatformVersion = result[1]
-			platformsMap := viper.GetStringMapString(constant.PLATFORM_VERSIONS)
-			logger.Trace(fmt.Sprintf("Platform Map: %v", platformsMap))
-			// Get the platform details from the map
-			platformName, found := platformsMap[result[1]]
+			// Get the platform details from the platform registry
+			platform, found := util.GetPlatformRegistry().GetPlatformByVersion(result[1])
 			if found {
-				logger.Debug("Platform name found in configs")
-				updateDescriptorV2.PlatformName = platformName
+				logger.Debug("Platform name found in the platform registry")
+				updateDescriptorV2.PlatformName = platform.Name
 			} else {
 				//If the platform name is not found, request the user
 				logger.Debug("No matching platform name found for:", result[1])
@@ -589,7 +642,7 @@ func processReadMeData(readMeDataString *string, updateDescriptorV2 *util.Update
 				// Regex has a one capturing group. So the jira ID will be in the 1st index.
 				logger.Debug(fmt.Sprintf("%d: %s", i, match[1]))
 				logger.Debug(fmt.Sprintf("ASSOCIATED_JIRAS_REGEX results is correct: %v", match))
-				updateDescriptorV2.BugFixes[match[1]] = util.GetJiraSummary(match[1])
+				updateDescriptorV2.BugFixes[match[1]] = util.GetIssueSummary(match[1])
 			}
 		}
 	} else {
@@ -630,6 +683,11 @@ func setRemainingValuesInUpdateDescriptorsV2(updateDescriptorV2 *util.UpdateDesc
 
 // Sets the update number in update-descriptor.yaml
 func setUpdateNumber(updateDescriptorV2 *util.UpdateDescriptorV2) {
+	if updateNumber := util.GetProjectConfig().UpdateNumber; len(updateNumber) > 0 {
+		logger.Debug(fmt.Sprintf("Update number %s is pinned in the project configuration", updateNumber))
+		updateDescriptorV2.UpdateNumber = updateNumber
+		return
+	}
 	var updateNumber string
 	for {
 		util.PrintInBold("Enter 'update number': ")
@@ -652,12 +710,24 @@ func setUpdateNumber(updateDescriptorV2 *util.UpdateDescriptorV2) {
 
 // Sets the platform name and version in update-descriptor.yaml
 func setPlatformNameAndVersion(updateDescriptorV2 *util.UpdateDescriptorV2) {
-userInputLoop:
+	if platformName, platformVersion, pinned := getPinnedPlatform(); pinned {
+		logger.Debug(fmt.Sprintf("Platform %s %s is pinned in the project configuration", platformName,
+			platformVersion))
+		updateDescriptorV2.PlatformName = platformName
+		updateDescriptorV2.PlatformVersion = platformVersion
+		return
+	}
+	platforms := util.GetPlatformRegistry().GetSelectablePlatforms()
+	if len(platforms) == 0 {
+		util.HandleErrorAndExit(errors.New(fmt.Sprintf("no platforms found. Please configure the %s in the "+
+			"config.yaml", constant.PLATFORMS)))
+	}
 	for {
 		util.PrintInBold(fmt.Sprintf("Select the platform name and version from following: \n"))
-		util.PrintInBold(fmt.Sprintf("\t1. wilkes \t 4.4.0\n"))
-		util.PrintInBold(fmt.Sprintf("\t2. hamming \t 5.0.0\n"))
-		util.PrintInBold(fmt.Sprintf("Enter your preference [1/2]: "))
+		for i, platform := range platforms {
+			util.PrintInBold(fmt.Sprintf("\t%d. %s \t %s\n", i+1, platform.Name, platform.Version))
+		}
+		util.PrintInBold(fmt.Sprintf("Enter your preference [1/%d]: ", len(platforms)))
 		userInput, err := util.GetUserInput()
 		if err != nil {
 			util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
@@ -666,24 +736,62 @@ userInputLoop:
 		if err != nil {
 			util.HandleErrorAndExit(err, "Error occurred while casting the user input to int")
 		}
-		switch preference {
-		case 1:
-			updateDescriptorV2.PlatformName = "wilkes"
-			updateDescriptorV2.PlatformVersion = "4.4.0"
-			fmt.Println(fmt.Sprintf("platform name: 'wilkes' and platform version: '4.4.0' selected\n"))
-			break userInputLoop
-		case 2:
-			updateDescriptorV2.PlatformName = "hamming"
-			updateDescriptorV2.PlatformVersion = "5.0.0"
-			fmt.Println(fmt.Sprintf("platform name: 'hamming' and platform version: '5.0.0' selected\n"))
-			break userInputLoop
-		default:
+		if preference < 1 || preference > len(platforms) {
 			util.PrintError("Invalid input")
 			continue
 		}
+		platform := platforms[preference-1]
+		updateDescriptorV2.PlatformName = platform.Name
+		updateDescriptorV2.PlatformVersion = platform.Version
+		fmt.Println(fmt.Sprintf("platform name: '%s' and platform version: '%s' selected\n", platform.Name,
+			platform.Version))
+		return
 	}
 }
 
+// This function will set the update number and the platform pinned in the project configuration, overriding the values
+// found in the README.txt.
+func applyPinnedUpdateDetails(updateDescriptorV2 *util.UpdateDescriptorV2) {
+	if updateNumber := util.GetProjectConfig().UpdateNumber; len(updateNumber) > 0 &&
+		updateDescriptorV2.UpdateNumber != updateNumber {
+		// Values are not found in the README.txt if it is not present, which is not worth a warning
+		if len(updateDescriptorV2.UpdateNumber) > 0 {
+			util.PrintWarning(fmt.Sprintf("Update number '%s' found in the %s is overridden by '%s' in the %s.",
+				updateDescriptorV2.UpdateNumber, constant.README_FILE, updateNumber, constant.PROJECT_CONFIG_FILE))
+		}
+		updateDescriptorV2.UpdateNumber = updateNumber
+	}
+	if platformName, platformVersion, pinned := getPinnedPlatform(); pinned &&
+		(updateDescriptorV2.PlatformName != platformName || updateDescriptorV2.PlatformVersion != platformVersion) {
+		if len(updateDescriptorV2.PlatformName) > 0 || len(updateDescriptorV2.PlatformVersion) > 0 {
+			util.PrintWarning(fmt.Sprintf("Platform '%s %s' found in the %s is overridden by '%s %s' in the %s.",
+				updateDescriptorV2.PlatformName, updateDescriptorV2.PlatformVersion, constant.README_FILE,
+				platformName, platformVersion, constant.PROJECT_CONFIG_FILE))
+		}
+		updateDescriptorV2.PlatformName = platformName
+		updateDescriptorV2.PlatformVersion = platformVersion
+	}
+}
+
+// This function returns the platform name and version pinned in the project configuration. If only the platform
+// version is pinned, the platform name is taken from the platform registry.
+func getPinnedPlatform() (string, string, bool) {
+	projectConfig := util.GetProjectConfig()
+	if len(projectConfig.PlatformVersion) == 0 {
+		return "", "", false
+	}
+	if len(projectConfig.PlatformName) > 0 {
+		return projectConfig.PlatformName, projectConfig.PlatformVersion, true
+	}
+	platform, found := util.GetPlatformRegistry().GetPlatformByVersion(projectConfig.PlatformVersion)
+	if !found {
+		util.HandleErrorAndExit(errors.New(fmt.Sprintf("no platform found for the platform_version '%s' in the "+
+			"%s. Please set the platform_name as well", projectConfig.PlatformVersion,
+			constant.PROJECT_CONFIG_FILE)))
+	}
+	return platform.Name, platform.Version, true
+}
+
 // Sets the applies to in update-descriptor.yaml
 func setAppliesTo(updateDescriptorV2 *util.UpdateDescriptorV2) {
 	util.PrintInBold(fmt.Sprintf("\nEnter applies to: "))
@@ -737,9 +845,19 @@ userInputLoop:
 	updateDescriptorV2.BugFixes = bugFixes
 }
 
-// Used for getting JIRA_KEY_SUMMARY/GITHUB_ISSUE_SUMMARY for the given JIRA_KEY/GITHUB_ISSUE.
+// Used for getting JIRA_KEY_SUMMARY/GITHUB_ISSUE_SUMMARY for the given JIRA_KEY/GITHUB_ISSUE. The summary is looked up
+// from the configured issue trackers and the user is prompted to confirm it. If the summary is not found or not
+// confirmed, the user is prompted to enter the summary.
 func getJiraSummary(jiraKey string) string {
-	var jiraSummary string
+	jiraSummary := util.GetIssueSummary(jiraKey)
+	if jiraSummary != constant.JIRA_SUMMARY_DEFAULT {
+		util.PrintInBold(fmt.Sprintf("\tSummary of '%s': %s\n\tUse this summary? [Y/n]: ", jiraKey, jiraSummary))
+		preference, err := util.GetUserInput()
+		util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
+		if len(preference) == 0 || util.ProcessUserPreference(preference) == constant.YES {
+			return jiraSummary
+		}
+	}
 	for {
 		util.PrintInBold(fmt.Sprintf("\tEnter JIRA_KEY_SUMMARY/GITHUB_ISSUE_SUMMARY for '%s': ", jiraKey))
 		jiraSum, err := util.GetUserInput()
@@ -755,6 +873,78 @@ func getJiraSummary(jiraKey string) string {
 	return jiraSummary
 }
 
+// This function will prompt for the `description`, `instructions` and `bug_fixes` fields of the given
+// update-descriptor3.yaml and save it in the given update directory.
+func completeUpdateDescriptorV3(updateDirectoryPath string, updateDescriptorV3 *util.UpdateDescriptorV3) {
+	util.PrintInBold(fmt.Sprintf("\nEnter the details of the update to complete the %s,\n",
+		constant.UPDATE_DESCRIPTOR_V3_FILE))
+	description := getDescriptorFieldValue("description", updateDescriptorV3.Description,
+		constant.DEFAULT_DESCRIPTION, false)
+	updateDescriptorV3.Description = description
+	instructions := getDescriptorFieldValue("instructions", updateDescriptorV3.Instructions,
+		constant.DEFAULT_INSTRUCTIONS, true)
+	updateDescriptorV3.Instructions = instructions
+	updateDescriptorV3.BugFixes = getBugFixesV3(updateDescriptorV3.BugFixes)
+	createUpdateDescriptorV3(updateDirectoryPath, updateDescriptorV3)
+}
+
+// This function will prompt for a multi-line value of the given field of the update-descriptor3.yaml. If an editor is
+// configured using the VISUAL or EDITOR environment variables, the current value is opened in the editor. Otherwise
+// lines are read until an empty line is entered.
+func getDescriptorFieldValue(field, currentValue, defaultValue string, isEmptyAllowed bool) string {
+	if currentValue == defaultValue {
+		currentValue = ""
+	}
+	for {
+		var value string
+		var err error
+		if len(util.GetEditorCommand()) > 0 {
+			instructions := fmt.Sprintf("Enter the %s of the update. Lines starting with '%s' are ignored.",
+				field, constant.EDITOR_COMMENT_PREFIX)
+			value, err = util.EditInEditor(currentValue, instructions)
+			util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while editing the %s.", field))
+		} else {
+			util.PrintInBold(fmt.Sprintf("\tEnter the %s (enter an empty line to finish):\n", field))
+			value, err = util.GetMultiLineUserInput()
+			util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
+		}
+		value = strings.TrimSpace(value)
+		if len(value) == 0 && !isEmptyAllowed {
+			util.PrintErrorWithTab(fmt.Sprintf("Empty input detected, please enter a valid %s", field))
+			continue
+		}
+		logger.Debug(fmt.Sprintf("%s: %s", field, value))
+		return value
+	}
+}
+
+// This function will prompt for the bug fixes of the update-descriptor3.yaml. Bug fixes which are already in the
+// update-descriptor3.yaml are kept, except the default value.
+func getBugFixesV3(currentBugFixes map[string]string) map[string]string {
+	bugFixes := make(map[string]string)
+	for jiraKey, jiraSummary := range currentBugFixes {
+		if jiraKey != constant.DEFAULT_JIRA_KEY {
+			bugFixes[jiraKey] = jiraSummary
+		}
+	}
+	util.PrintInBold("\tEnter bug fixes (enter an empty JIRA_KEY/GITHUB ISSUE URL to finish),\n")
+	for {
+		util.PrintInBold(fmt.Sprintf("\tEnter JIRA_KEY/GITHUB ISSUE URL: "))
+		jiraKey, err := util.GetUserInput()
+		util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
+		if jiraKey == "" {
+			if len(bugFixes) == 0 {
+				util.PrintErrorWithTab("Empty input detected, please enter a valid JIRA_KEY/GITHUB ISSUE URL")
+				continue
+			}
+			break
+		}
+		bugFixes[jiraKey] = getJiraSummary(jiraKey)
+	}
+	logger.Debug(fmt.Sprintf("bug_fixes: %v", bugFixes))
+	return bugFixes
+}
+
 // Creates the updateDescriptorV2 for saving.
 func createUpdateDescriptorV2(updateDirectoryPath string, updateDescriptorV2 *util.UpdateDescriptorV2) {
 	// Marshall update descriptor struct
@@ -814,12 +1004,21 @@ func getUpdateName(updateDescriptorV2 *util.UpdateDescriptorV2, updateNamePrefix
 	return updateName
 }
 
-// This function acts as a helper method for downloading a file from given url to the given location.
+// This function acts as a helper method for downloading a file from given url to the given location. In the offline
+// mode, the file is copied from the product catalog instead.
 func downloadFile(directory, urlName, downloadUrl, fileName string) {
-	url, exists := os.LookupEnv(urlName)
+	if isOfflineEnabled {
+		filePath, err := util.GetOfflineResourceFilePath(fileName)
+		util.HandleErrorAndExit(err)
+		err = util.CopyFile(filePath, path.Join(directory, fileName))
+		util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while copying the file '%v' from: %s.", fileName,
+			filePath))
+		return
+	}
+	url, exists := util.LookupResourceFileURL(urlName)
 	if !exists {
 		url = downloadUrl
-		logger.Debug(fmt.Sprintf("Environment variable '%s' is not set. Getting file from: %s",
+		logger.Debug(fmt.Sprintf("'%s' is not configured. Getting file from: %s",
 			urlName, downloadUrl))
 	}
 	err := util.DownloadFile(path.Join(directory, fileName), url)
@@ -833,8 +1032,17 @@ func downloadFile(directory, urlName, downloadUrl, fileName string) {
 // this function will decide how to proceed.
 func handleNoMatch(filename string, isDir bool, allFilesMap map[string]data, rootNode *node,
 	updateDescriptor *util.UpdateDescriptorV2) error {
-	//todo: Check OSGi bundles in the plugins directory
 	logger.Debug(fmt.Sprintf("[NO MATCH] %s", filename))
+	// A jar with a different version in its name might be a newer version of an OSGi bundle in the distribution
+	if !isDir {
+		isHandled, err := handleBundleVersionChange(filename, allFilesMap, rootNode, updateDescriptor)
+		if err != nil {
+			return err
+		}
+		if isHandled {
+			return nil
+		}
+	}
 	util.PrintInBold(fmt.Sprintf("'%s' not found in distribution. ", filename))
 	for {
 		// Get the user preference
@@ -863,6 +1071,79 @@ func handleNoMatch(filename string, isDir bool, allFilesMap map[string]data, roo
 	}
 }
 
+// This function will check whether the given file is a newer version of an OSGi bundle which is already in the
+// distribution. If so, the new jar will be copied next to the old jar and the old jar will be added as a removed
+// file. Returns true if the file was handled as a bundle version change.
+func handleBundleVersionChange(filename string, allFilesMap map[string]data, rootNode *node,
+	updateDescriptor *util.UpdateDescriptorV2) (bool, error) {
+	fileInfo := allFilesMap[filename]
+	if len(fileInfo.bundleSymbolicName) == 0 {
+		return false, nil
+	}
+	logger.Debug(fmt.Sprintf("[BUNDLE] %s ; symbolic name: %s ; version: %s", filename,
+		fileInfo.bundleSymbolicName, fileInfo.bundleVersion))
+	matches := make(map[string]*node)
+	FindBundleMatches(rootNode, fileInfo.bundleSymbolicName, matches)
+	logger.Debug(fmt.Sprintf("bundle matches: %v", matches))
+	if len(matches) == 0 {
+		return false, nil
+	}
+
+	// Sort the matching locations so that the output is consistent
+	allPaths := make([]string, 0)
+	for bundlePath := range matches {
+		allPaths = append(allPaths, bundlePath)
+	}
+	sort.Strings(allPaths)
+
+	util.PrintInfo(fmt.Sprintf("'%s' is version '%s' of the OSGi bundle '%s' which is found in the distribution "+
+		"at following locations.", filename, fileInfo.bundleVersion, fileInfo.bundleSymbolicName))
+	for _, bundlePath := range allPaths {
+		util.PrintInfo(fmt.Sprintf("\t%s (version '%s')", path.Join("CARBON_HOME", bundlePath),
+			matches[bundlePath].bundleVersion))
+		if util.CompareBundleVersions(fileInfo.bundleVersion, matches[bundlePath].bundleVersion) <= 0 {
+			util.PrintWarning(fmt.Sprintf("Version '%s' of '%s' is not newer than version '%s' found in the "+
+				"distribution.", fileInfo.bundleVersion, filename, matches[bundlePath].bundleVersion))
+		}
+	}
+	for {
+		util.PrintInBold("Do you want to replace the existing bundle(s) with this version? [Y/n]: ")
+		preference, err := util.GetUserInput()
+		if len(preference) == 0 {
+			preference = "y"
+		}
+		util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
+
+		userPreference := util.ProcessUserPreference(preference)
+		switch userPreference {
+		case constant.YES:
+			updateRoot := viper.GetString(constant.UPDATE_ROOT)
+			for _, bundlePath := range allPaths {
+				oldBundle := matches[bundlePath]
+				// Place the new jar next to the old jar
+				logger.Debug(fmt.Sprintf("[Copy] %s ; From: %s ; To: %s", filename, updateRoot,
+					oldBundle.parent.relativeLocation))
+				err = copyFile(filename, updateRoot, oldBundle.parent.relativeLocation, rootNode,
+					updateDescriptor)
+				if err != nil {
+					return true, err
+				}
+				// Old jar should be removed when the update is applied
+				if !util.IsStringIsInSlice(oldBundle.relativeLocation, updateDescriptor.FileChanges.RemovedFiles) {
+					updateDescriptor.FileChanges.RemovedFiles = append(
+						updateDescriptor.FileChanges.RemovedFiles, oldBundle.relativeLocation)
+				}
+				util.PrintInfo(fmt.Sprintf("'%s' added to the removed files.", oldBundle.relativeLocation))
+			}
+			return true, nil
+		case constant.NO:
+			return false, nil
+		default:
+			util.PrintError("Invalid preference. Enter Y for Yes or N for No.")
+		}
+	}
+}
+
 // This function will handle the situations where the user want to add a file as a new file which was not found in the
 // distribution.
 func handleNewFile(filename string, isDir bool, rootNode *node, allFilesMap map[string]data,
@@ -1003,20 +1284,20 @@ func handleSingleMatch(filename string, matchingNode *node, isDir bool, allFiles
 		// Copy all matching files to the temp directory
 		for _, match := range allMatchingFiles {
 			logger.Debug(fmt.Sprintf("match: %s", match))
-			// Check md5 only if the md5 checking is not disabled
+			// Check checksum only if the checksum checking is not disabled
 			if !viper.GetBool(constant.CHECK_MD5_DISABLED) {
-				logger.Debug(fmt.Sprintf("Checking md5: %v", filename))
+				logger.Debug(fmt.Sprintf("Checking checksum: %v", filename))
 				data := allFilesMap[match]
-				// Check whether the md5 matches or not
+				// Check whether the checksum matches or not
 				fileLocation := path.Join(matchingNode.relativeLocation, match)
-				md5Matches := CheckMD5(rootNode, strings.Split(fileLocation, "/"), data.md5)
-				if md5Matches {
-					util.PrintInfo(fmt.Sprintf("File '%v' not copied because MD5 matches with "+
+				checksumMatches := CheckChecksum(rootNode, strings.Split(fileLocation, "/"), data.checksum)
+				if checksumMatches {
+					util.PrintInfo(fmt.Sprintf("File '%v' not copied because checksum matches with "+
 						"the already existing file.", match))
-					logger.Debug("MD5 matches. Ignoring file.")
+					logger.Debug("Checksum matches. Ignoring file.")
 					continue
 				} else {
-					logger.Debug("MD5 does not match. Copying the file.")
+					logger.Debug("Checksum does not match. Copying the file.")
 				}
 			}
 			// Copy the file to temp directory
@@ -1026,21 +1307,21 @@ func handleSingleMatch(filename string, matchingNode *node, isDir bool, allFiles
 			util.HandleErrorAndExit(err)
 		}
 	} else {
-		// Check md5 only if the md5 checking is not disabled
+		// Check checksum only if the checksum checking is not disabled
 		if !viper.GetBool(constant.CHECK_MD5_DISABLED) {
-			logger.Debug(fmt.Sprintf("Checking md5: %v", filename))
+			logger.Debug(fmt.Sprintf("Checking checksum: %v", filename))
 			data := allFilesMap[filename]
-			// Check whether the md5 matches or not
+			// Check whether the checksum matches or not
 			fileLocation := path.Join(matchingNode.relativeLocation, filename)
-			md5Matches := CheckMD5(rootNode, strings.Split(fileLocation, "/"), data.md5)
-			if md5Matches {
-				util.PrintInfo(fmt.Sprintf("File '%v' not copied because MD5 matches with the "+
+			checksumMatches := CheckChecksum(rootNode, strings.Split(fileLocation, "/"), data.checksum)
+			if checksumMatches {
+				util.PrintInfo(fmt.Sprintf("File '%v' not copied because checksum matches with the "+
 					"already existing file.", filename))
-				logger.Debug("MD5 matches. Ignoring file.")
-				// If md5 does not match, return
+				logger.Debug("Checksum matches. Ignoring file.")
+				// If checksum matches, return
 				return nil
 			} else {
-				logger.Debug("MD5 does not match. Copying the file.")
+				logger.Debug("Checksum does not match. Copying the file.")
 			}
 		}
 		// Copy the file to temp directory
@@ -1105,13 +1386,46 @@ func handleMultipleMatches(filename string, isDir bool, matches map[string]*node
 		util.PrintWarning(fmt.Sprintf("0 entered. Skipping copying '%s'.", filename))
 		return nil
 	}
+	selectedLocations := make([]string, 0)
+	for _, selectedIndex := range selectedIndices {
+		logger.Debug(fmt.Sprintf("[MULTIPLE MATCHES] Selected path: %s ; %s", selectedIndex,
+			indexMap[selectedIndex]))
+		selectedLocations = append(selectedLocations, indexMap[selectedIndex])
+	}
+	return copyToLocations(filename, isDir, selectedLocations, allFilesMap, rootNode, updateDescriptor)
+}
+
+// This function will print a warning for each of the given destinations pinned for the given file/directory which does
+// not exist in the distribution, as a mistyped destination would silently add the file/directory to a new location.
+func warnMissingDestinations(filename string, destinations []string, rootNode *node) {
+	for _, destination := range getMissingDestinations(destinations, rootNode) {
+		util.PrintWarning(fmt.Sprintf("Destination '%s' pinned for '%s' in the %s does not exist in the "+
+			"distribution. '%s' will be added as a new file.", destination, filename, constant.PROJECT_CONFIG_FILE,
+			path.Join(destination, filename)))
+	}
+}
+
+// This function returns the given destination directories which do not exist in the distribution. Empty destination
+// is the distribution root which always exists.
+func getMissingDestinations(destinations []string, rootNode *node) []string {
+	missingDestinations := make([]string, 0)
+	for _, destination := range destinations {
+		cleanDestination := path.Clean(filepath.ToSlash(destination))
+		if cleanDestination != "." && !PathExists(rootNode, cleanDestination, true) {
+			missingDestinations = append(missingDestinations, destination)
+		}
+	}
+	return missingDestinations
+}
+
+// This function will copy the given file/directory in the root of the update directory to the given locations
+// relative to the distribution root.
+func copyToLocations(filename string, isDir bool, locations []string, allFilesMap map[string]data, rootNode *node,
+	updateDescriptor *util.UpdateDescriptorV2) error {
 	updateRoot := viper.GetString(constant.UPDATE_ROOT)
 	if isDir {
 		// Copy the directory to all selected locations
-		for _, selectedIndex := range selectedIndices {
-			pathInDistribution := indexMap[selectedIndex]
-			logger.Debug(fmt.Sprintf("[MULTIPLE MATCHES] Selected path: %s ; %s", selectedIndex,
-				pathInDistribution))
+		for _, pathInDistribution := range locations {
 
 			// Get all matching files (files which are in the directory and subdirectories)
 			allMatchingFiles := getAllMatchingFiles(filename, allFilesMap)
@@ -1120,19 +1434,19 @@ func handleMultipleMatches(filename string, isDir bool, matches map[string]*node
 			// Copy all the matching files to temp directory
 			for _, match := range allMatchingFiles {
 				logger.Debug(fmt.Sprintf("match: %s", match))
-				// Check md5 if the md5 checking is not disabled
+				// Check checksum if the checksum checking is not disabled
 				if !viper.GetBool(constant.CHECK_MD5_DISABLED) {
 					data := allFilesMap[match]
-					// Check whether the md5 matches or not
+					// Check whether the checksum matches or not
 					fileLocation := strings.Split(path.Join(pathInDistribution, match), "/")
-					md5Matches := CheckMD5(rootNode, fileLocation, data.md5)
-					if md5Matches {
-						util.PrintInfo(fmt.Sprintf("File '%v' not copied because MD5 "+
+					checksumMatches := CheckChecksum(rootNode, fileLocation, data.checksum)
+					if checksumMatches {
+						util.PrintInfo(fmt.Sprintf("File '%v' not copied because checksum "+
 							"matches with the already existing file.", match))
-						logger.Debug("MD5 matches. Ignoring file.")
+						logger.Debug("Checksum matches. Ignoring file.")
 						continue
 					}
-					logger.Debug("MD5 does not match. Copying the file.")
+					logger.Debug("Checksum does not match. Copying the file.")
 				}
 				logger.Debug(fmt.Sprintf("[Copy] %s ; From: %s ; To: %s", filename, updateRoot,
 					pathInDistribution))
@@ -1142,27 +1456,24 @@ func handleMultipleMatches(filename string, isDir bool, matches map[string]*node
 		}
 	} else {
 		// Copy the file to all selected locations
-		for _, selectedIndex := range selectedIndices {
-			pathInDistribution := indexMap[selectedIndex]
-			// Check md5 if the md5 checking is not disabled
+		for _, pathInDistribution := range locations {
+			// Check checksum if the checksum checking is not disabled
 			if !viper.GetBool(constant.CHECK_MD5_DISABLED) {
 				data := allFilesMap[filename]
-				// Check whether the md5 matches or not
+				// Check whether the checksum matches or not
 				fileLocation := strings.Split(path.Join(pathInDistribution, filename), "/")
-				md5Matches := CheckMD5(rootNode, fileLocation, data.md5)
-				if md5Matches {
-					// If md5 matches, print warning msg and continue with the next selected
+				checksumMatches := CheckChecksum(rootNode, fileLocation, data.checksum)
+				if checksumMatches {
+					// If checksum matches, print warning msg and continue with the next selected
 					// location
-					util.PrintInfo(fmt.Sprintf("File '%v' not copied because MD5 matches "+
+					util.PrintInfo(fmt.Sprintf("File '%v' not copied because checksum matches "+
 						"with the already existing file.", filename))
-					logger.Debug("MD5 matches. Ignoring file.")
+					logger.Debug("Checksum matches. Ignoring file.")
 					continue
 				}
-				logger.Debug("MD5 does not match. Copying the file.")
+				logger.Debug("Checksum does not match. Copying the file.")
 			}
 			// Copy the file to temp location
-			logger.Debug(fmt.Sprintf("[MULTIPLE MATCHES] Selected path: %s ; %s", selectedIndex,
-				pathInDistribution))
 			logger.Debug(fmt.Sprintf("[Copy] %s ; From: %s ; To: %s", filename, updateRoot,
 				pathInDistribution))
 			err := copyFile(filename, updateRoot, pathInDistribution, rootNode, updateDescriptor)
@@ -1192,9 +1503,10 @@ func readDirectory(root string, ignoredFiles map[string]bool) (map[string]data,
 	allFilesMap := make(map[string]data)
 	rootLevelDirectoriesMap := make(map[string]bool)
 	rootLevelFilesMap := make(map[string]bool)
+	hashAlgorithm := util.GetHashAlgorithm()
 
 	// Walk and read the directory structure
-	filepath.Walk(root, func(absolutePath string, fileInfo os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(absolutePath string, fileInfo os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -1214,6 +1526,12 @@ func readDirectory(root string, ignoredFiles map[string]bool) (map[string]data,
 				return nil
 			}
 		}
+		// Symbolic links are not followed by the walk. So they should be resolved explicitly or refused
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			if err := checkSymlink(absolutePath); err != nil {
+				return err
+			}
+		}
 		// Get the relative path. This is used as the key of the map
 		trimPattern := root + "/"
 		if strings.HasSuffix(root, "/") {
@@ -1243,25 +1561,64 @@ func readDirectory(root string, ignoredFiles map[string]bool) (map[string]data,
 				rootLevelFilesMap[fileInfo.Name()] = false
 			}
 
-			// We need other information like md5 sum because we are storing details of all files in the
+			// We need other information like checksum because we are storing details of all files in the
 			// allFilesMap
-			logger.Trace("[MD5] Calculating MD5")
-			//If it is a file, calculate md5 sum
-			md5Sum, err := util.GetMD5(absolutePath)
+			logger.Trace(fmt.Sprintf("[CHECKSUM] Calculating %s", hashAlgorithm))
+			//If it is a file, calculate checksum
+			checksum, err := util.GetChecksum(absolutePath, hashAlgorithm)
 			if err != nil {
 				return err
 			}
-			logger.Trace(fmt.Sprintf("%s : %s = %s", absolutePath, fileInfo.Name(), md5Sum))
-			info.md5 = md5Sum
+			logger.Trace(fmt.Sprintf("%s : %s = %s", absolutePath, fileInfo.Name(), checksum))
+			info.checksum = checksum
 			info.isDir = false
+
+			// Read OSGi bundle details of jars. These are used to identify version changes of bundles.
+			if strings.HasSuffix(fileInfo.Name(), constant.JAR_EXTENSION) {
+				bundleInfo, err := util.GetBundleInfoOfFile(absolutePath)
+				if err != nil {
+					logger.Debug(fmt.Sprintf("Error occurred while reading the manifest of %s: %v", absolutePath,
+						err))
+				} else if bundleInfo != nil {
+					info.bundleSymbolicName = bundleInfo.SymbolicName
+					info.bundleVersion = bundleInfo.Version
+				}
+			}
 		}
 		// Add the entry to the allFilesMap
 		allFilesMap[relativePath] = info
 		return nil
 	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	return allFilesMap, rootLevelDirectoriesMap, rootLevelFilesMap, nil
 }
 
+// This function will check the symbolic link in the given location. Symbolic links are refused unless resolving them
+// is enabled using the --resolve-symlinks flag. Only links to files are resolved, in which case the actual file will
+// be copied to the update.
+func checkSymlink(location string) error {
+	if !viper.GetBool(constant.RESOLVE_SYMLINKS) {
+		return errors.New(fmt.Sprintf("symbolic link found at '%s'. Symbolic links are not allowed in updates. "+
+			"Replace it with the actual file or use the --resolve-symlinks flag.", location))
+	}
+	target, err := filepath.EvalSymlinks(location)
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to resolve the symbolic link '%s'. %v", location, err))
+	}
+	targetInfo, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if targetInfo.IsDir() {
+		return errors.New(fmt.Sprintf("symbolic link '%s' points to the directory '%s'. Symbolic links to "+
+			"directories are not supported.", location, target))
+	}
+	logger.Debug(fmt.Sprintf("Symbolic link '%s' resolved to '%s'.", location, target))
+	return nil
+}
+
 // This function will read the zip file in the given location.
 func readZip(location string) (node, error) {
 	rootNode := createNewNode()
@@ -1275,6 +1632,7 @@ func readZip(location string) (node, error) {
 
 	productName := viper.GetString(constant.PRODUCT_NAME)
 	logger.Debug(fmt.Sprintf("productName: %s", productName))
+	hashAlgorithm := util.GetHashAlgorithm()
 	// Iterate through each file in the zip file
 	for _, file := range zipReader.Reader.File {
 		zippedFile, err := file.Open()
@@ -1285,10 +1643,11 @@ func readZip(location string) (node, error) {
 		// Don't use defer here because otherwise there will be too many open files and it will cause a panic
 		zippedFile.Close()
 
-		// Calculate the md5 of the file
-		hash := md5.New()
-		hash.Write(data)
-		md5Hash := hex.EncodeToString(hash.Sum(nil))
+		// Calculate the checksum of the file
+		checksum, err := util.GetChecksumOfData(data, hashAlgorithm)
+		if err != nil {
+			return rootNode, err
+		}
 
 		// Get the relative path of the file
 		logger.Trace(fmt.Sprintf("file.Name: %s", file.Name))
@@ -1296,16 +1655,33 @@ func readZip(location string) (node, error) {
 		relativePath := util.GetRelativePath(file)
 
 		// Add the file to root node
-		AddToRootNode(&rootNode, strings.Split(relativePath, "/"), file.FileInfo().IsDir(), md5Hash)
+		AddToRootNode(&rootNode, strings.Split(relativePath, "/"), file.FileInfo().IsDir(), checksum)
 		if !file.FileInfo().IsDir() {
 			fileMap[relativePath] = false
 		}
+
+		// Store OSGi bundle details of jars. These are used to identify version changes of bundles.
+		if !file.FileInfo().IsDir() && strings.HasSuffix(relativePath, constant.JAR_EXTENSION) {
+			bundleInfo, err := util.GetBundleInfo(data)
+			if err != nil {
+				logger.Debug(fmt.Sprintf("Error occurred while reading the manifest of %s: %v", relativePath, err))
+			} else if bundleInfo != nil {
+				SetBundleInfo(&rootNode, strings.Split(relativePath, "/"), bundleInfo)
+			}
+			// Store the entries of jars. These are used to report the changed entries of modified jars.
+			jarEntries, err := util.GetJarEntries(data)
+			if err != nil {
+				logger.Debug(fmt.Sprintf("Error occurred while reading the entries of %s: %v", relativePath, err))
+			} else if jarNode := GetNode(&rootNode, strings.Split(relativePath, "/")); jarNode != nil {
+				jarNode.jarEntries = jarEntries
+			}
+		}
 	}
 	return rootNode, nil
 }
 
 // This function will add a new node.
-func AddToRootNode(root *node, path []string, isDir bool, md5Hash string) *node {
+func AddToRootNode(root *node, path []string, isDir bool, checksum string) *node {
 	logger.Trace("Checking: %s : %s", path[0], path)
 
 	// If the current path element is the last element, add it as a new node.
@@ -1314,7 +1690,7 @@ func AddToRootNode(root *node, path []string, isDir bool, md5Hash string) *node
 		newNode := createNewNode()
 		newNode.name = path[0]
 		newNode.isDir = isDir
-		newNode.md5Hash = md5Hash
+		newNode.checksum = checksum
 		if len(root.relativeLocation) == 0 {
 			newNode.relativeLocation = path[0]
 		} else {
@@ -1342,11 +1718,39 @@ func AddToRootNode(root *node, path []string, isDir bool, md5Hash string) *node
 			node = &newNode
 		}
 		// Recursively call the function for the rest of the path elements.
-		AddToRootNode(node, path[1:], isDir, md5Hash)
+		AddToRootNode(node, path[1:], isDir, checksum)
 	}
 	return root
 }
 
+// This function will set the OSGi bundle details of the node in the given path.
+func SetBundleInfo(root *node, path []string, bundleInfo *util.BundleInfo) {
+	childNode, found := root.childNodes[path[0]]
+	if !found {
+		logger.Trace(fmt.Sprintf("%s NOT found", path[0]))
+		return
+	}
+	if len(path) > 1 {
+		SetBundleInfo(childNode, path[1:], bundleInfo)
+		return
+	}
+	childNode.bundleSymbolicName = bundleInfo.SymbolicName
+	childNode.bundleVersion = bundleInfo.Version
+}
+
+// This function will return the node in the given path. If the path is not found, nil is returned.
+func GetNode(root *node, path []string) *node {
+	childNode, found := root.childNodes[path[0]]
+	if !found {
+		logger.Trace(fmt.Sprintf("%s NOT found", path[0]))
+		return nil
+	}
+	if len(path) > 1 {
+		return GetNode(childNode, path[1:])
+	}
+	return childNode
+}
+
 // This function is a helper function which calls NodeExists() and checks whether a node exists in the given path and
 // the type(file/dir) is correct.
 func PathExists(rootNode *node, relativePath string, isDir bool) bool {
@@ -1374,20 +1778,21 @@ func NodeExists(rootNode *node, path []string, isDir bool) bool {
 	return false
 }
 
-// This function will check the MD5 hash of the file in the provided path in the distribution with the provided hash.
-func CheckMD5(rootNode *node, path []string, md5 string) bool {
+// This function will check the checksum of the file in the provided path in the distribution with the provided
+// checksum.
+func CheckChecksum(rootNode *node, path []string, checksum string) bool {
 	logger.Trace(fmt.Sprintf("All: %v", rootNode.childNodes))
 	logger.Trace(fmt.Sprintf("Checking: %s", path[0]))
 	childNode, found := rootNode.childNodes[path[0]]
 	// If the path element is found, that means it is in the tree
 	if found {
 		// If there are more path elements than 1, continue recursively. Otherwise check whether it has the
-		// given md5 or not and return.
+		// given checksum or not and return.
 		logger.Trace(fmt.Sprintf("%s found", path[0]))
 		if len(path) > 1 {
-			return CheckMD5(childNode, path[1:], md5)
+			return CheckChecksum(childNode, path[1:], checksum)
 		} else {
-			return childNode.isDir == false && childNode.md5Hash == md5
+			return childNode.isDir == false && childNode.checksum == checksum
 		}
 	}
 	// If the path element is not found, return false
@@ -1414,6 +1819,18 @@ func FindMatches(root *node, name string, isDir bool, matches map[string]*node)
 	}
 }
 
+// This function will find all OSGi bundles in the distribution which have the provided symbolic name. Key of the
+// matches map is the relative location of the bundle.
+func FindBundleMatches(root *node, symbolicName string, matches map[string]*node) {
+	for _, childNode := range root.childNodes {
+		if childNode.isDir {
+			FindBundleMatches(childNode, symbolicName, matches)
+		} else if childNode.bundleSymbolicName == symbolicName {
+			matches[childNode.relativeLocation] = childNode
+		}
+	}
+}
+
 // This will return a map of files which would be ignored when reading the update directory.
 func getIgnoredFilesInUpdate() map[string]bool {
 	filesMap := make(map[string]bool)
@@ -1429,9 +1846,22 @@ func getIgnoredFilesInUpdate() map[string]bool {
 	for _, file := range viper.GetStringSlice(constant.RESOURCE_FILES_SKIP) {
 		filesMap[file] = true
 	}
+	// Checksum manifest is generated by the tool, so an existing one in the update directory is ignored
+	filesMap[constant.CHECKSUMS_FILE] = true
+	// Project configuration is only used by the tool and it is not a part of the update
+	filesMap[constant.PROJECT_CONFIG_FILE] = true
 	return filesMap
 }
 
+// This function will load the project configuration (.wum-uc.yaml) of the given update directory.
+func loadProjectConfig(updateDirectoryPath string) {
+	err := util.LoadProjectConfig(updateDirectoryPath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while loading the %s.", constant.PROJECT_CONFIG_FILE))
+	for _, filePath := range util.GetProjectConfig().GetFilePaths() {
+		util.PrintInfo(fmt.Sprintf("Using the project configuration in '%s'.", filePath))
+	}
+}
+
 // This will return a map of files which would be copied to the temp directory before creating the update zip. Key is
 // the file name and value is whether the file is mandatory or not.
 func getResourceFiles() map[string]bool {
@@ -1444,6 +1874,8 @@ func getResourceFiles() map[string]bool {
 	for _, file := range viper.GetStringSlice(constant.RESOURCE_FILES_OPTIONAL) {
 		filesMap[file] = false
 	}
+	// Checksum manifest is generated when creating the update zip
+	filesMap[constant.CHECKSUMS_FILE] = false
 	return filesMap
 }
 
@@ -1489,6 +1921,10 @@ func copyResourceFilesToTempDir(resourceFilesMap map[string]bool) error {
 	util.CreateDirectory(destination)
 	// Iterate through all resource files
 	for filename, isMandatory := range resourceFilesMap {
+		// Checksum manifest is generated when creating the update zip, so it is not copied
+		if filename == constant.CHECKSUMS_FILE {
+			continue
+		}
 		updateRoot := viper.GetString(constant.UPDATE_ROOT)
 		source := path.Join(updateRoot, filename)
 		destination = path.Join(constant.TEMP_DIR, updateName, filename)
@@ -1578,6 +2014,9 @@ func copyFile(filename string, locationInUpdate, relativeLocationInTemp string,
 	if contains {
 		updateDescriptor.FileChanges.ModifiedFiles = append(updateDescriptor.FileChanges.ModifiedFiles,
 			relativePath)
+		if strings.HasSuffix(filename, constant.JAR_EXTENSION) {
+			printJarEntryChanges(source, filepath.ToSlash(relativePath), rootNode)
+		}
 	} else {
 		updateDescriptor.FileChanges.AddedFiles = append(updateDescriptor.FileChanges.AddedFiles,
 			relativePath)
@@ -1585,8 +2024,28 @@ func copyFile(filename string, locationInUpdate, relativeLocationInTemp string,
 	return nil
 }
 
-// This function will create a zip file from the source to the target folder.
+// This function will compare the entries of the given jar in the update with the jar in the same location of the
+// distribution and print the added, removed and modified entries.
+func printJarEntryChanges(source, relativePath string, rootNode *node) {
+	jarNode := GetNode(rootNode, strings.Split(relativePath, "/"))
+	if jarNode == nil || jarNode.jarEntries == nil {
+		logger.Debug(fmt.Sprintf("Entries of '%s' not found in the distribution.", relativePath))
+		return
+	}
+	updatedJarEntries, err := util.GetJarEntriesOfFile(source)
+	if err != nil {
+		util.PrintWarning(fmt.Sprintf("Error occurred while reading the entries of '%s': %v", source, err))
+		return
+	}
+	util.PrintJarEntryChanges(relativePath, util.CompareJarEntries(jarNode.jarEntries, updatedJarEntries))
+}
+
+// This function will create a zip file from the source to the target folder. If deterministic zips are enabled, the
+// same source will always produce a byte-identical zip file.
 func ZipFile(source, target string) error {
+	if viper.GetBool(constant.DETERMINISTIC_ZIP) {
+		return createDeterministicZip(source, target)
+	}
 	zipfile, err := os.Create(target)
 	if err != nil {
 		return err
@@ -1606,10 +2065,14 @@ func ZipFile(source, target string) error {
 		baseDir = filepath.Base(source)
 	}
 
-	filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return errors.New(fmt.Sprintf("symbolic link found at '%s'. Symbolic links cannot be added to "+
+				"the update zip.", path))
+		}
 
 		header, err := zip.FileInfoHeader(info)
 		if err != nil {
@@ -1645,19 +2108,155 @@ func ZipFile(source, target string) error {
 		_, err = io.Copy(writer, file)
 		return err
 	})
-	return err
+}
+
+// This function will create a zip file from the source to the target folder. Entries are sorted by name, timestamps are
+// set to SOURCE_DATE_EPOCH (or a fixed time if it is not set), permissions are canonicalised to 0755 for directories
+// and executables and 0644 for other files, and all files are compressed using deflate.
+func createDeterministicZip(source, target string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	var baseDir string
+	if info.IsDir() {
+		baseDir = filepath.Base(source)
+	}
+
+	// Collect all the entries first, so they can be written in a sorted order
+	entries := make(map[string]string)
+	entryInfos := make(map[string]os.FileInfo)
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return errors.New(fmt.Sprintf("symbolic link found at '%s'. Symbolic links cannot be added to "+
+				"the update zip.", path))
+		}
+		name := info.Name()
+		if baseDir != "" {
+			name = filepath.Join(baseDir, strings.TrimPrefix(path, source))
+		}
+		//To support archives created under Windows and to be correctly handled in Linux.
+		name = filepath.ToSlash(name)
+		if info.IsDir() {
+			name += "/"
+		}
+		entries[name] = path
+		entryInfos[name] = info
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0)
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zipfile, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer zipfile.Close()
+	archive := zip.NewWriter(zipfile)
+
+	timestamp := getZipTimestamp()
+	for _, name := range names {
+		info := entryInfos[name]
+		header := &zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: timestamp,
+		}
+		header.SetMode(getCanonicalFileMode(info))
+		if info.IsDir() {
+			header.Method = zip.Store
+		}
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+		file, err := os.Open(entries[name])
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(writer, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// This function returns the timestamp used for the entries of deterministic zips. If the SOURCE_DATE_EPOCH environment
+// variable is set, it is used. Otherwise a fixed timestamp is used.
+func getZipTimestamp() time2.Time {
+	if sourceDateEpoch, exists := os.LookupEnv(constant.SOURCE_DATE_EPOCH); exists {
+		seconds, err := strconv.ParseInt(strings.TrimSpace(sourceDateEpoch), 10, 64)
+		if err == nil {
+			return time2.Unix(seconds, 0).UTC()
+		}
+		util.PrintWarning(fmt.Sprintf("Invalid value '%s' found in %s environment variable. Using the default "+
+			"timestamp.", sourceDateEpoch, constant.SOURCE_DATE_EPOCH))
+	}
+	return time2.Unix(constant.DEFAULT_ZIP_TIMESTAMP, 0).UTC()
+}
+
+// This function returns the canonical permissions of the given file. Directories and files which are executable by
+// anyone get 0755 and other files get 0644.
+func getCanonicalFileMode(info os.FileInfo) os.FileMode {
+	if info.IsDir() {
+		return os.ModeDir | 0755
+	}
+	if info.Mode().Perm()&0111 != 0 {
+		return 0755
+	}
+	return 0644
 }
 
 func setProductChangesInUpdateDescriptorV3(partialUpdatedProducts *util.PartialUpdatedProducts) *util.ProductChanges {
 	productChanges := &util.ProductChanges{}
 	productChanges.ProductName = partialUpdatedProducts.ProductName
-	productChanges.ProductVersion = partialUpdatedProducts.BaseVersion + "." + partialUpdatedProducts.Tag
+	productChanges.ProductVersion = partialUpdatedProducts.BaseVersion
+	// Products in the product catalog might not have a tag
+	if len(partialUpdatedProducts.Tag) > 0 {
+		productChanges.ProductVersion += "." + partialUpdatedProducts.Tag
+	}
 	productChanges.AddedFiles = partialUpdatedProducts.AddedFiles
 	productChanges.RemovedFiles = partialUpdatedProducts.RemovedFiles
 	productChanges.ModifiedFiles = partialUpdatedProducts.ModifiedFiles
 	return productChanges
 }
 
+// This function will ask the user whether files in the distribution are removed by the update and add them to the
+// update-descriptor.yaml.
+func requestRemovedFiles(distributionName string, updateDescriptorV2 *util.UpdateDescriptorV2) {
+	for {
+		util.PrintInBold(fmt.Sprintf("\nAre the existing files in %s removed from this update? [y"+
+			"/n]: ",
+			distributionName))
+		preference, err := util.GetUserInput()
+		util.HandleErrorAndExit(err, "Error occurred while getting input from the user.")
+		userPreference := util.ProcessUserPreference(preference)
+		switch userPreference {
+		case constant.YES:
+			appendRemovedFilesToUpdateDescriptor(updateDescriptorV2)
+			return
+		case constant.NO:
+			return
+		default:
+			util.PrintError("Invalid preference. Enter y for Yes or n for No.")
+		}
+	}
+}
+
 // This will append removed files to update-descriptor.yaml
 func appendRemovedFilesToUpdateDescriptor(updateDescriptorV2 *util.UpdateDescriptorV2) {
 userInputLoop:
@@ -1725,6 +2324,7 @@ func continueResumedUpdateCreation() {
 		util.HandleErrorAndExit(err, "error occurred while un-marshaling the ", wumucResumeFilePath)
 	}
 	logger.Trace(fmt.Sprintf("Unmarshalling %s file successfully completed", wumucResumeFilePath))
+	loadProjectConfig(resumedFile.ResourceDirectoryPath)
 
 	// Check if the update zip has already being created
 	if resumedFile.IsUpdateZipCreated {
@@ -1760,6 +2360,7 @@ func continueResumedUpdateCreation() {
 		updateZipName := resumedFile.UpdateName + ".zip"
 		cleanupChannel := util.HandleInterrupts(func() {
 			util.CleanUpFile(updateZipName)
+			util.CleanUpFile(util.GetSignatureFilePath(updateZipName))
 			util.CleanUpFile(destination)
 		})
 
@@ -1774,6 +2375,8 @@ func continueResumedUpdateCreation() {
 			resumedFile.ExplodedUpdateDirectoryPath))
 		// Create the update zip
 		createUpdateZip(&resumedFile)
+		// Sign the created update zip
+		signUpdateZip(&resumedFile)
 		// Validate the created update zip
 		validateUpdate(&resumedFile)
 
@@ -1805,13 +2408,127 @@ func createUpdateZip(resumeFile *ResumeFile) {
 	updateZipName := resumeFile.UpdateName + ".zip"
 	logger.Debug(fmt.Sprintf("Name of the update zip: %s", updateZipName))
 	logger.Debug(fmt.Sprintf("Creating the update zip %s", updateZipName))
-	err := ZipFile(resumeFile.ExplodedUpdateDirectoryPath, updateZipName)
+	// Remove the signature of a previously created update zip as it is no longer valid
+	util.CleanUpFile(util.GetSignatureFilePath(updateZipName))
+	// Apply the lint rules to the README.txt as it is not added to the update zip. Other files are linted when the
+	// created update zip is validated
+	lintReadMe(resumeFile.ResourceDirectoryPath)
+	// Scan the update for secrets before it is packaged
+	findings, err := util.ScanDirectoryForSecrets(resumeFile.ExplodedUpdateDirectoryPath)
+	if err != nil {
+		util.HandleErrorAndExit(err, "error occurred when scanning the update for secrets.")
+	}
+	checkSecretFindings(findings)
+	// Write the checksums of all the files to the update root
+	err = util.WriteChecksumManifest(resumeFile.ExplodedUpdateDirectoryPath)
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when writing the %s.", constant.CHECKSUMS_FILE))
+	}
+	err = ZipFile(resumeFile.ExplodedUpdateDirectoryPath, updateZipName)
 	if err != nil {
 		util.HandleErrorAndExit(err, "error occurred when compressing the update zip.")
 	}
 	logger.Debug(fmt.Sprintf("Update zip %s created successfully.", updateZipName))
 }
 
+// This function will apply the configured lint rules to the README.txt in the given update directory if it exists.
+func lintReadMe(updateDirectoryPath string) {
+	readMePath := path.Join(updateDirectoryPath, constant.README_FILE)
+	exists, err := util.IsFileExists(readMePath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when checking the existance of %s", readMePath))
+	if !exists {
+		logger.Debug(fmt.Sprintf("%s not found. Skipping linting it.", readMePath))
+		return
+	}
+	data, err := ioutil.ReadFile(readMePath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when reading %s", readMePath))
+	rules, err := util.GetLintRules()
+	util.HandleErrorAndExit(err)
+	lintContext := util.NewLintContext(nil)
+	lintContext.AddFile(constant.README_FILE, data)
+	violations, err := util.ApplyLintRules(rules, lintContext)
+	util.HandleErrorAndExit(err)
+	err = util.ReportLintViolations(violations)
+	util.HandleErrorAndExit(err)
+}
+
+// This function will report the given secret findings which are not allowed by the secrets allowlist. The tool will exit
+// if such findings exist unless the --allow-secrets flag is given.
+func checkSecretFindings(findings []util.SecretFinding) {
+	allowlist, err := getSecretsAllowlist()
+	util.HandleErrorAndExit(err, "error occurred when reading the secrets allowlist.")
+	findings = allowlist.Filter(findings)
+	if len(findings) == 0 {
+		logger.Debug("No secrets found in the update.")
+		return
+	}
+	util.PrintWarning("Following files of the update may contain secrets such as passwords, private keys or " +
+		"keystores.")
+	for _, finding := range findings {
+		fmt.Println(fmt.Sprintf("\t%s", finding))
+	}
+	if isSecretsAllowed {
+		util.PrintWarning("Continuing as the --allow-secrets flag is given.")
+		return
+	}
+	util.HandleErrorAndExit(errors.New(fmt.Sprintf("%d possible secrets found in the update. Remove them, add "+
+		"known false positives to the secrets allowlist or use the --allow-secrets flag.", len(findings))))
+}
+
+// This function returns the secrets allowlist given by the --secrets-allowlist flag. If the flag is not given, the
+// secrets-allowlist.txt in $WUMUC_HOME is used if it exists.
+func getSecretsAllowlist() (*util.SecretsAllowlist, error) {
+	allowlistPath := secretsAllowlistPath
+	if len(allowlistPath) == 0 {
+		allowlistPath = filepath.Join(viper.GetString(constant.WUM_UC_HOME), constant.SECRETS_ALLOWLIST_FILE)
+		exists, err := util.IsFileExists(allowlistPath)
+		if err != nil || !exists {
+			logger.Debug(fmt.Sprintf("Secrets allowlist not found at '%s'", allowlistPath))
+			return nil, err
+		}
+	}
+	logger.Debug(fmt.Sprintf("Reading the secrets allowlist at '%s'", allowlistPath))
+	return util.LoadSecretsAllowlist(allowlistPath)
+}
+
+// This function will sign the created update zip using the signing key configured in the config.yaml. If a signing key
+// is not configured, the update zip will not be signed.
+func signUpdateZip(resumeFile *ResumeFile) {
+	signingKey := util.GetWUMUCConfigs().SigningKey
+	if len(signingKey) == 0 {
+		logger.Debug("Signing key is not configured. Skipping signing the update zip.")
+		return
+	}
+	updateZipName := resumeFile.UpdateName + ".zip"
+	logger.Debug(fmt.Sprintf("Signing %s using %s", updateZipName, signingKey))
+	signatureFilePath, err := util.SignFile(updateZipName, signingKey)
+	if err != nil {
+		util.HandleErrorAndExit(err, "error occurred when signing the update zip.")
+	}
+	fmt.Println(fmt.Sprintf("'%s' successfully signed. Signature is written to '%s'.", updateZipName,
+		signatureFilePath))
+}
+
+// This function will copy the signature of the created update zip to the given directory if the update zip is signed.
+func copySignatureFile(updateZipName, directoryPath string) {
+	signatureFileName := util.GetSignatureFilePath(updateZipName)
+	exists, err := util.IsFileExists(signatureFileName)
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when checking the existance of %s",
+			signatureFileName))
+	}
+	if !exists {
+		logger.Debug(fmt.Sprintf("%s does not exist as the update zip is not signed", signatureFileName))
+		return
+	}
+	err = util.CopyFile(signatureFileName, path.Join(directoryPath, signatureFileName))
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when copying the %s to %s", signatureFileName,
+			directoryPath))
+	}
+	logger.Debug(fmt.Sprintf("%s successfully copied to %s", signatureFileName, directoryPath))
+}
+
 // This function will validate the created update zip before committing it to the pointed SVN.
 func validateUpdate(resumeFile *ResumeFile) {
 	// Get absolute location of the created update zip
@@ -1844,7 +2561,7 @@ func commitUpdateToSVN(resumeFile *ResumeFile) {
 	}
 	fmt.Fprintln(os.Stdout)
 
-	SVNURI := constant.SVN_UPDATE_REPO + "/" + resumeFile.PlatformName + "/" + constant.SVN_UPDATES
+	SVNURI := util.GetPlatformRegistry().GetSVNURL(resumeFile.PlatformName)
 	updateSVNURI := SVNURI + "/" + constant.SVN_UPDATE + resumeFile.UpdateNumber
 
 	// First need to checkout whether the given update is already committed to the SVN.
@@ -1937,6 +2654,7 @@ func commitNewUpdateToSVN(resumeFile *ResumeFile, updateSVNURI string, password
 	}
 	logger.Debug(fmt.Sprintf("%s successfully copied to %s for commiting to the SVN", updateZipName,
 		updateDirectoryPath))
+	copySignatureFile(updateZipName, updateDirectoryPath)
 
 	// Add the update directory to the SVN
 	performSVNAddCommand(resumeFile)
@@ -2019,15 +2737,26 @@ func commitUpgradedUpdatesWithPreservingPreviousUpdatesAtSVN(resumeFile *ResumeF
 	oldUpdateZipNewPath := path.Join(oldUpdatesDirectoryPath, newUpdateZipName)
 	logger.Trace(fmt.Sprintf("Old update zip new path: %s", oldUpdateZipNewPath))
 	performSVNMoveFile(resumeFile, oldUpdateZipCurrentPath, oldUpdateZipNewPath)
+	// Move the signature of the previous update zip along with it
+	oldSignatureCurrentPath := util.GetSignatureFilePath(oldUpdateZipCurrentPath)
+	exists, err := util.IsFileExists(oldSignatureCurrentPath)
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when checking the existance of %s",
+			oldSignatureCurrentPath))
+	}
+	if exists {
+		performSVNMoveFile(resumeFile, oldSignatureCurrentPath, util.GetSignatureFilePath(oldUpdateZipNewPath))
+	}
 	// Copy newly created update.zip to the checkout directory
 	newUpdateZipPath := path.Join(updateDirectoryPath, updateZipName)
 	logger.Debug(fmt.Sprintf("Copying newly created %s to %s ...", updateZipName, updateDirectoryPath))
-	err := util.CopyFile(updateZipName, newUpdateZipPath)
+	err = util.CopyFile(updateZipName, newUpdateZipPath)
 	if err != nil {
 		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when copying %s to %s", updateZipName, updateDirectoryPath))
 	}
 	logger.Debug(fmt.Sprintf("Copying newly created %s to %s completed successfully", updateZipName,
 		updateDirectoryPath))
+	copySignatureFile(updateZipName, updateDirectoryPath)
 
 	// Add file changes to the SVN pending change list
 	performSVNAddCommand(resumeFile)
@@ -2064,13 +2793,23 @@ func performSVNAddCommand(resumeFile *ResumeFile) {
 	updateDirectory := constant.SVN_UPDATE + resumeFile.UpdateNumber
 	updateDirectoryPath := path.Join(WUMUCHome, updateDirectory)
 
-	// Add newly created update zip to SVN pending change list.
-	logger.Debug(fmt.Sprintf("Adding newly created %s to the pending change list ...", updateZipName))
-	SVNAddCommand := exec.Command(constant.SVN_COMMAND, constant.ADD_COMMAND, updateZipName)
+	// Add newly created update zip and its signature to SVN pending change list.
+	filesToAdd := []string{updateZipName}
+	signatureFileName := util.GetSignatureFilePath(updateZipName)
+	exists, err := util.IsFileExists(path.Join(updateDirectoryPath, signatureFileName))
+	if err != nil {
+		util.HandleErrorAndExit(err, fmt.Sprintf("error occurred when checking the existance of %s",
+			signatureFileName))
+	}
+	if exists {
+		filesToAdd = append(filesToAdd, signatureFileName)
+	}
+	logger.Debug(fmt.Sprintf("Adding newly created %s to the pending change list ...", filesToAdd))
+	SVNAddCommand := exec.Command(constant.SVN_COMMAND, append([]string{constant.ADD_COMMAND}, filesToAdd...)...)
 	SVNAddCommand.Dir = updateDirectoryPath
 	SVNAddCommand.Stdout = &stdOut
 	SVNAddCommand.Stderr = &stdErr
-	err := SVNAddCommand.Run()
+	err = SVNAddCommand.Run()
 	logger.Trace(fmt.Sprintf("stdout of SVNAddCommand \n%v", stdOut.String()))
 	if err != nil {
 		logger.Trace(fmt.Sprintf("stderr of SVNAddCommand \n%v", stdErr.String()))
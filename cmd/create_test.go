@@ -15,9 +15,17 @@
 package cmd
 
 import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/wso2/update-creator-tool/constant"
 	"github.com/wso2/update-creator-tool/util"
 )
@@ -67,12 +75,12 @@ func TestAddToRootNode(t *testing.T) {
 		t.Errorf("Test failed, node '%v' not found.", nodeName)
 	}
 
-	if nodeC.md5Hash != hash {
-		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeC.md5Hash)
+	if nodeC.checksum != hash {
+		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeC.checksum)
 	}
 
 	if nodeC.isDir != isDir {
-		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeC.md5Hash)
+		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeC.checksum)
 	}
 
 	//Add new file
@@ -103,12 +111,12 @@ func TestAddToRootNode(t *testing.T) {
 		t.Errorf("Test failed, node '%v' not found.", nodeName)
 	}
 
-	if nodeD.md5Hash != hash {
-		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeD.md5Hash)
+	if nodeD.checksum != hash {
+		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeD.checksum)
 	}
 
 	if nodeD.isDir != isDir {
-		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeD.md5Hash)
+		t.Errorf("Test failed, expected: %v, actual: %v", hash, nodeD.checksum)
 	}
 
 }
@@ -177,3 +185,194 @@ func TestPathExists(t *testing.T) {
 		t.Errorf("Test failed, expected: %v, actual: %v", expected, exists)
 	}
 }
+
+func TestZipFileDeterministic(t *testing.T) {
+	viper.Set(constant.DETERMINISTIC_ZIP, true)
+	defer viper.Set(constant.DETERMINISTIC_ZIP, nil)
+
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-0001")
+	files := map[string]string{
+		"update-descriptor3.yaml":            "update_number: \"0001\"\n",
+		"carbon.home/bin/wso2server.sh":      "#!/bin/sh\n",
+		"carbon.home/repository/lib/a.jar":   "jar content",
+		"carbon.home/repository/conf/b.xml":  "<conf/>",
+		"carbon.home/repository/conf/z.yaml": "key: value",
+	}
+	for filePath, content := range files {
+		absolutePath := filepath.Join(source, filepath.FromSlash(filePath))
+		if err = os.MkdirAll(filepath.Dir(absolutePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(absolutePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = os.Chmod(filepath.Join(source, "carbon.home", "bin", "wso2server.sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	createZip := func(target string, modifiedTime time.Time) []byte {
+		err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Chtimes(path, modifiedTime, modifiedTime)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ZipFile(source, target); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first := createZip(filepath.Join(tempDir, "first.zip"), time.Now().Add(-48*time.Hour))
+	second := createZip(filepath.Join(tempDir, "second.zip"), time.Now())
+	if !bytes.Equal(first, second) {
+		t.Errorf("Test failed, zips created from the same inputs are not identical")
+	}
+}
+
+func TestReadDirectorySymlinks(t *testing.T) {
+	defer viper.Set(constant.RESOLVE_SYMLINKS, nil)
+
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	updateDir := filepath.Join(tempDir, "update")
+	if err = os.MkdirAll(filepath.Join(updateDir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(tempDir, "a.jar")
+	if err = ioutil.WriteFile(target, []byte("jar content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(target, filepath.Join(updateDir, "lib", "a.jar")); err != nil {
+		t.Skip("symbolic links are not supported:", err)
+	}
+
+	//Symbolic links are refused by default
+	viper.Set(constant.RESOLVE_SYMLINKS, false)
+	if _, _, _, err = readDirectory(updateDir, nil); err == nil {
+		t.Errorf("Test failed, symbolic link was not refused")
+	}
+
+	//Symbolic links to files are resolved if enabled
+	viper.Set(constant.RESOLVE_SYMLINKS, true)
+	allFilesMap, _, _, err := readDirectory(updateDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := util.GetChecksum(target, util.GetHashAlgorithm())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allFilesMap["lib/a.jar"].checksum != expected {
+		t.Errorf("Test failed, expected: %s, actual: %s", expected, allFilesMap["lib/a.jar"].checksum)
+	}
+
+	//Symbolic links to directories are not supported
+	if err = os.Symlink(tempDir, filepath.Join(updateDir, "dir")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err = readDirectory(updateDir, nil); err == nil {
+		t.Errorf("Test failed, symbolic link to a directory was not refused")
+	}
+}
+
+func TestReadDirectoryIgnoresProjectConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, name := range []string{constant.PROJECT_CONFIG_FILE, "a.jar"} {
+		if err = ioutil.WriteFile(filepath.Join(tempDir, name), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	allFilesMap, _, rootLevelFilesMap, err := readDirectory(tempDir, getIgnoredFilesInUpdate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := allFilesMap[constant.PROJECT_CONFIG_FILE]; found || rootLevelFilesMap[constant.PROJECT_CONFIG_FILE] {
+		t.Errorf("Test failed, %s was not ignored", constant.PROJECT_CONFIG_FILE)
+	}
+	if _, found := allFilesMap["a.jar"]; !found {
+		t.Errorf("Test failed, a.jar was ignored")
+	}
+}
+
+func TestZipFilePreservesExecutableBits(t *testing.T) {
+	for _, deterministic := range []bool{true, false} {
+		viper.Set(constant.DETERMINISTIC_ZIP, deterministic)
+
+		tempDir, err := ioutil.TempDir("", "wum-uc-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		source := filepath.Join(tempDir, "update")
+		if err = os.MkdirAll(filepath.Join(source, "bin"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		script := filepath.Join(source, "bin", "wso2server.sh")
+		if err = ioutil.WriteFile(script, []byte("#!/bin/sh\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.Chmod(script, 0755); err != nil {
+			t.Fatal(err)
+		}
+		target := filepath.Join(tempDir, "update.zip")
+		if err = ZipFile(source, target); err != nil {
+			t.Fatal(err)
+		}
+
+		zipReader, err := zip.OpenReader(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, file := range zipReader.File {
+			if file.Name == "update/bin/wso2server.sh" {
+				found = true
+				if file.Mode().Perm() != 0755 {
+					t.Errorf("Test failed, deterministic: %v, expected: %v, actual: %v", deterministic,
+						os.FileMode(0755), file.Mode().Perm())
+				}
+			}
+		}
+		zipReader.Close()
+		if !found {
+			t.Errorf("Test failed, script not found in the zip, deterministic: %v", deterministic)
+		}
+	}
+	viper.Set(constant.DETERMINISTIC_ZIP, nil)
+}
+
+func TestGetMissingDestinations(t *testing.T) {
+	root := createNewNode()
+	AddToRootNode(&root, strings.Split("repository/components/plugins/a.jar", "/"), false, "hash1")
+	destinations := []string{"", "repository/components/plugins/", "repository/components/dropins", "bin"}
+	missingDestinations := getMissingDestinations(destinations, &root)
+	expected := []string{"repository/components/dropins", "bin"}
+	if !reflect.DeepEqual(missingDestinations, expected) {
+		t.Errorf("Test failed, expected: %v, actual: %v", expected, missingDestinations)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/renstrom/dedent"
+	"github.com/spf13/cobra"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+)
+
+// Values used to print help command.
+var (
+	devServerCmdUse       = "dev-server <fixtures_dir>"
+	devServerCmdShortDesc = "Start a mock WUM server for development and tests"
+	devServerCmdLongDesc  = dedent.Dedent(`
+		This command will start a local mock of the WUM servers. The token API,
+		the applicable products API, the wum-uc version check and the resource
+		files (LICENSE.txt, NOT_A_CONTRIBUTION.txt) are served from the given
+		fixtures directory. Requests can be failed with a given status code
+		using the --fail flag (ie. --fail /token=401:1). The server only
+		accepts connections from this machine unless another host is given
+		using the --host flag.`)
+)
+
+var devServerHost string
+var devServerPort int
+var devServerFaults []string
+
+// devServerCmd represents the dev-server command.
+var devServerCmd = &cobra.Command{
+	Use:   devServerCmdUse,
+	Short: devServerCmdShortDesc,
+	Long:  devServerCmdLongDesc,
+	Run:   initializeDevServerCommand,
+}
+
+// This function will be called first and this will add flags to the command.
+func init() {
+	RootCmd.AddCommand(devServerCmd)
+
+	devServerCmd.Flags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
+	devServerCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
+	devServerCmd.Flags().StringVar(&devServerHost, "host", constant.DEV_SERVER_DEFAULT_HOST, "Address which the "+
+		"server listens on. Anyone who can connect to it can get tokens and change the faults")
+	devServerCmd.Flags().IntVarP(&devServerPort, "port", "p", constant.DEV_SERVER_DEFAULT_PORT, "Port of the "+
+		"server")
+	devServerCmd.Flags().StringArrayVar(&devServerFaults, "fail", []string{}, "Fail the requests to the given path "+
+		"with the given status code, in the format path=status[:count]")
+}
+
+// This function will be called when the dev-server command is called.
+func initializeDevServerCommand(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		util.HandleErrorAndExit(errors.New("invalid number of arguments. Run 'wum-uc dev-server --help' to " +
+			"view help"))
+	}
+	startDevServer(args[0])
+}
+
+// This function will start the mock WUM server which serves the given fixtures directory.
+func startDevServer(fixturesDirectory string) {
+	setLogLevel()
+	logger.Debug("[dev-server] command called")
+
+	info, err := os.Stat(fixturesDirectory)
+	if err != nil || !info.IsDir() {
+		util.HandleErrorAndExit(errors.New(fmt.Sprintf("fixtures directory '%s' does not exist",
+			fixturesDirectory)))
+	}
+	server := util.NewDevServer(fixturesDirectory)
+	for _, fault := range devServerFaults {
+		devServerFault, err := util.ParseDevServerFault(fault)
+		util.HandleErrorAndExit(err)
+		server.AddFault(devServerFault)
+	}
+
+	address := net.JoinHostPort(devServerHost, fmt.Sprint(devServerPort))
+	serverURL := fmt.Sprintf("http://localhost:%d", devServerPort)
+	if !isLoopbackHost(devServerHost) {
+		util.PrintWarning(fmt.Sprintf("Mock WUM server listens on '%s'. It can be accessed from other machines.",
+			address))
+		if ip := net.ParseIP(devServerHost); len(devServerHost) > 0 && (ip == nil || !ip.IsUnspecified()) {
+			serverURL = "http://" + address
+		}
+	}
+	util.PrintInfo(fmt.Sprintf("Mock WUM server started at %s. Use the following in the config.yaml in "+
+		"$WUMUC_HOME:", serverURL))
+	fmt.Println(fmt.Sprintf("serverurl: %s\ntokenurl: %s/%s\nversionurl: %s\n", serverURL, serverURL,
+		constant.TOKEN_API_CONTEXT, serverURL))
+	util.PrintInfo("and set the following environment variables:")
+	fmt.Println(fmt.Sprintf("%s=%s%s%s\n%s=%s%s%s", constant.LICENSE_URL, serverURL,
+		constant.DEV_SERVER_LICENSE_CONTEXT, constant.LICENSE_FILE, constant.NOT_A_CONTRIBUTION_URL, serverURL,
+		constant.DEV_SERVER_LICENSE_CONTEXT, constant.NOT_A_CONTRIBUTION_FILE))
+	// Checksums of the resource files are given using the environment variables, as the validation downloads them
+	// from the WSO2 servers otherwise
+	printDevServerChecksum(server, constant.LICENSE_SHA256, constant.LICENSE_FILE)
+	printDevServerChecksum(server, constant.NOT_A_CONTRIBUTION_SHA256, constant.NOT_A_CONTRIBUTION_FILE)
+	fmt.Println()
+
+	err = http.ListenAndServe(address, server)
+	util.HandleErrorAndExit(err, "Error occurred while running the mock WUM server.")
+}
+
+// This function checks whether the given host only accepts connections from the same machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// This function will print the environment variable which gives the checksum of the given resource file served by the
+// development server, if the file exists in the fixtures directory.
+func printDevServerChecksum(server *util.DevServer, envName, fileName string) {
+	checksum, err := server.GetResourceFileChecksum(fileName)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Checksum of %s is not available: %v", fileName, err))
+		return
+	}
+	fmt.Println(fmt.Sprintf("%s=%s", envName, checksum))
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+)
+
+// Environment variable which makes the test binary run the wum-uc command given in its arguments, so that the commands
+// can be tested end to end without building wum-uc.
+const testCommandEnv = "WUMUC_TEST_COMMAND"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testCommandEnv) == "true" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// This function will write the given files to the given directory. Key of the files map is the path of the file
+// relative to the directory and the value is the content.
+func writeTestFiles(t *testing.T, directory string, files map[string]string) {
+	for filePath, content := range files {
+		absolutePath := filepath.Join(directory, filepath.FromSlash(filePath))
+		if err := os.MkdirAll(filepath.Dir(absolutePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(absolutePath, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// This function will run wum-uc with the given arguments in the given directory and return the output. The test binary
+// is copied to the directory first, as the create command resolves its temp directory relative to the executable.
+func runTestCommand(t *testing.T, directory string, env []string, stdin string, args ...string) (string, error) {
+	executable := filepath.Join(directory, "wum-uc")
+	if exists, _ := util.IsFileExists(executable); !exists {
+		testBinary, err := os.Executable()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = util.CopyFile(testBinary, executable); err != nil {
+			t.Fatal(err)
+		}
+	}
+	command := exec.Command(executable, args...)
+	command.Dir = directory
+	command.Env = append(append(os.Environ(), env...), testCommandEnv+"=true")
+	command.Stdin = strings.NewReader(stdin)
+	output, err := command.CombinedOutput()
+	t.Logf("wum-uc %s\n%s", strings.Join(args, " "), output)
+	return string(output), err
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	hosts := map[string]bool{
+		constant.DEV_SERVER_DEFAULT_HOST: true,
+		"localhost":                      true,
+		"::1":                            true,
+		"":                               false,
+		"0.0.0.0":                        false,
+		"192.168.1.10":                   false,
+	}
+	for host, expected := range hosts {
+		if actual := isLoopbackHost(host); actual != expected {
+			t.Errorf("Test failed for '%s', expected: %v, actual: %v", host, expected, actual)
+		}
+	}
+}
+
+func TestCreateAndValidateWithDevServer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("svn is mocked using a shell script")
+	}
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Product catalog of the server makes the update partially applicable, so that the update-descriptor.yaml is not
+	// created and the user is not prompted for its values
+	fixturesDirectory := filepath.Join(tempDir, "fixtures")
+	writeTestFiles(t, fixturesDirectory, map[string]string{
+		constant.LICENSE_FILE:               "license",
+		constant.NOT_A_CONTRIBUTION_FILE:    "not a contribution",
+		"catalog/wilkes/wso2am-2.1.0.index": "bin/wso2server.sh\nrepository/conf/carbon.xml\n",
+		"catalog/wilkes/wso2is-5.4.0.index": "bin/wso2server.sh\n",
+		"bin/" + constant.SVN_COMMAND:       "#!/bin/sh\nexit 0\n",
+		"update/0001/" + constant.PROJECT_CONFIG_FILE: "update_number: \"0001\"\nplatform_name: wilkes\n" +
+			"platform_version: 4.4.0\n",
+		"update/0001/bin/wso2server.sh": "new",
+		"update/0001/carbon.xml":        "<new/>",
+	})
+	distributionPath := filepath.Join(tempDir, "wso2am-2.1.0.zip")
+	createTestZip(t, distributionPath, map[string]string{
+		"wso2am-2.1.0/bin/wso2server.sh":          "old",
+		"wso2am-2.1.0/repository/conf/carbon.xml": "<old/>",
+	})
+
+	// First request to the applicable products API fails as the access token is expired and the second one fails
+	// with a server error, so that renewing the access token and retrying are tested
+	devServer := util.NewDevServer(fixturesDirectory)
+	for _, fault := range []string{"/files=401:1", "/files=503:1"} {
+		devServerFault, err := util.ParseDevServerFault(fault)
+		if err != nil {
+			t.Fatal(err)
+		}
+		devServer.AddFault(devServerFault)
+	}
+	server := httptest.NewServer(devServer)
+	defer server.Close()
+
+	wumucHome := filepath.Join(tempDir, "home")
+	writeTestFiles(t, wumucHome, map[string]string{
+		constant.WUMUC_CONFIG_FILE: "serverurl: " + server.URL + "\ntokenurl: " + server.URL + "/" +
+			constant.TOKEN_API_CONTEXT + "\nversionurl: " + server.URL + "\nappkey: a2V5OnNlY3JldA==\n" +
+			"HTTP_CLIENT:\n  INITIAL_BACKOFF_IN_MILLISECONDS: 1\n",
+	})
+	licenseChecksum, err := devServer.GetResourceFileChecksum(constant.LICENSE_FILE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notAContributionChecksum, err := devServer.GetResourceFileChecksum(constant.NOT_A_CONTRIBUTION_FILE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := []string{
+		constant.WUM_UC_HOME + "=" + wumucHome,
+		"PATH=" + filepath.Join(fixturesDirectory, "bin") + string(os.PathListSeparator) + os.Getenv("PATH"),
+		constant.WUMUC_CLIENT_ID + "=ci",
+		constant.WUMUC_CLIENT_SECRET + "=secret",
+		constant.LICENSE_URL + "=" + server.URL + constant.DEV_SERVER_LICENSE_CONTEXT + constant.LICENSE_FILE,
+		constant.NOT_A_CONTRIBUTION_URL + "=" + server.URL + constant.DEV_SERVER_LICENSE_CONTEXT +
+			constant.NOT_A_CONTRIBUTION_FILE,
+		constant.LICENSE_SHA256 + "=" + licenseChecksum,
+		constant.NOT_A_CONTRIBUTION_SHA256 + "=" + notAContributionChecksum,
+	}
+	updateDirectory := filepath.Join(fixturesDirectory, "update", "0001")
+	workDirectory := filepath.Join(tempDir, "work")
+	if err = os.Mkdir(workDirectory, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// User is only asked whether files are removed by the update
+	output, err := runTestCommand(t, workDirectory, env, "n\n", "create", updateDirectory, distributionPath)
+	if err != nil || !strings.Contains(output, "Partially applicable products : [wso2is-5.4.0]") {
+		t.Fatalf("Test failed, create failed: %v", err)
+	}
+
+	// Fill the update-descriptor3.yaml as the developer would
+	descriptorPath := filepath.Join(updateDirectory, constant.UPDATE_DESCRIPTOR_V3_FILE)
+	data, err := ioutil.ReadFile(descriptorPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	descriptor := strings.NewReplacer(strings.TrimSpace(constant.DEFAULT_DESCRIPTION), "Fixes the startup script",
+		strings.TrimSpace(constant.DEFAULT_INSTRUCTIONS), "Restart the server",
+		constant.DEFAULT_JIRA_KEY+": "+constant.DEFAULT_JIRA_SUMMARY, "WSO2-1234: Startup fails").Replace(string(data))
+	if err = ioutil.WriteFile(descriptorPath, []byte(descriptor), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Update zip is created and validated, but committing it to the SVN fails as the password cannot be read
+	updateZipName := "WSO2-CARBON-UPDATE-4.4.0-0001.zip"
+	output, _ = runTestCommand(t, workDirectory, env, "", "create", "--continue")
+	if !strings.Contains(output, "validation successfully finished") ||
+		!strings.Contains(output, strings.TrimSuffix(updateZipName, ".zip")+"'.zip successfully created") {
+		t.Fatal("Test failed, update zip not created")
+	}
+
+	output, err = runTestCommand(t, workDirectory, env, "", "validate", "--project-config", updateDirectory,
+		updateZipName, distributionPath)
+	if err != nil || !strings.Contains(output, "validation successfully finished") {
+		t.Errorf("Test failed, validation failed: %v", err)
+	}
+
+	// Validation fails if the checksum of a resource file does not match
+	env = append(env, constant.LICENSE_SHA256+"="+strings.Repeat("0", len(licenseChecksum)))
+	if _, err = runTestCommand(t, workDirectory, env, "", "validate", updateZipName, distributionPath); err == nil {
+		t.Error("Test failed, update with an invalid LICENSE.txt validated")
+	}
+}
+
+func TestInitWithFailingCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("svn and the credential helper are mocked using shell scripts")
+	}
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	server := httptest.NewServer(util.NewDevServer(filepath.Join(tempDir, "fixtures")))
+	defer server.Close()
+
+	wumucHome := filepath.Join(tempDir, "home")
+	writeTestFiles(t, tempDir, map[string]string{
+		"bin/" + constant.SVN_COMMAND: "#!/bin/sh\nexit 0\n",
+		"bin/credential-helper":       "#!/bin/sh\ncat > /dev/null\n[ \"$1\" = get ]\n",
+		"home/" + constant.WUMUC_CONFIG_FILE: "serverurl: " + server.URL + "\ntokenurl: " + server.URL + "/" +
+			constant.TOKEN_API_CONTEXT + "\nversionurl: " + server.URL + "\nappkey: a2V5OnNlY3JldA==\n" +
+			"CREDENTIALS:\n  HELPER: " + filepath.Join(tempDir, "bin", "credential-helper") + "\n",
+	})
+	env := []string{
+		constant.WUM_UC_HOME + "=" + wumucHome,
+		"PATH=" + filepath.Join(tempDir, "bin") + string(os.PathListSeparator) + os.Getenv("PATH"),
+	}
+
+	// Credential helper does not have any credentials and fails to store them, so init should fail instead of
+	// printing done
+	workDirectory := filepath.Join(tempDir, "work")
+	if err = os.Mkdir(workDirectory, 0755); err != nil {
+		t.Fatal(err)
+	}
+	output, err := runTestCommand(t, workDirectory, env, "", "init", "-u", "user@example.com", "-p", "password")
+	if err == nil || strings.Contains(output, constant.DONE_MSG) {
+		t.Errorf("Test failed, init succeeded without storing the tokens: %s", output)
+	}
+}
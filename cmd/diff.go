@@ -0,0 +1,361 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/renstrom/dedent"
+	"github.com/spf13/cobra"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Values used to print help command.
+var (
+	diffCmdUse       = "diff <old_update_loc> <new_update_loc>"
+	diffCmdShortDesc = "Compare two update zips"
+	diffCmdLongDesc  = dedent.Dedent(`
+		This command will compare two revisions of an update. Fields of the
+		update descriptors, file lists of each product and checksums of all
+		files will be compared. Unified diffs will be shown for text files.`)
+)
+
+// Number of context lines shown in unified diffs.
+const diffContextLines = 3
+
+// This struct is used to store the content of an update zip which is needed for comparing.
+type updateContent struct {
+	updateDescriptorV2 util.UpdateDescriptorV2
+	updateDescriptorV3 util.UpdateDescriptorV3
+	// Key is the path of the file relative to the update root directory
+	files map[string]*updateFile
+}
+
+// This struct is used to store details of a file in an update zip.
+type updateFile struct {
+	checksum string
+	// Content is only stored for text files
+	content string
+}
+
+// diffCmd represents the diff command.
+var diffCmd = &cobra.Command{
+	Use:   diffCmdUse,
+	Short: diffCmdShortDesc,
+	Long:  diffCmdLongDesc,
+	Run:   initializeDiffCommand,
+}
+
+// This function will be called first and this will add flags to the command.
+func init() {
+	RootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
+	diffCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
+}
+
+// This function will be called when the diff command is called.
+func initializeDiffCommand(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		util.HandleErrorAndExit(errors.New("invalid number of arguments. Run 'wum-uc diff --help' to " +
+			"view help"))
+	}
+	startDiff(args[0], args[1])
+}
+
+// This function will compare the given update zips and print the differences.
+func startDiff(oldUpdateFilePath, newUpdateFilePath string) {
+	setLogLevel()
+	logger.Debug("diff command called")
+
+	for _, updateFilePath := range []string{oldUpdateFilePath, newUpdateFilePath} {
+		util.IsZipFile(constant.UPDATE, updateFilePath)
+		exists, err := util.IsFileExists(updateFilePath)
+		util.HandleErrorAndExit(err, "")
+		if !exists {
+			util.HandleErrorAndExit(errors.New(fmt.Sprintf("Entered update file does not exist at '%s'.",
+				updateFilePath)))
+		}
+	}
+
+	oldContent, err := readUpdateContent(oldUpdateFilePath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while reading '%s'.", oldUpdateFilePath))
+	newContent, err := readUpdateContent(newUpdateFilePath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while reading '%s'.", newUpdateFilePath))
+
+	isDescriptorChanged := diffUpdateDescriptors(oldContent, newContent)
+	isProductsChanged := diffProducts("Compatible products", oldContent.updateDescriptorV3.CompatibleProducts,
+		newContent.updateDescriptorV3.CompatibleProducts)
+	isPartialProductsChanged := diffProducts("Partially applicable products",
+		oldContent.updateDescriptorV3.PartiallyApplicableProducts,
+		newContent.updateDescriptorV3.PartiallyApplicableProducts)
+	isFilesChanged := diffFiles(oldContent.files, newContent.files)
+
+	if !isDescriptorChanged && !isProductsChanged && !isPartialProductsChanged && !isFilesChanged {
+		fmt.Println("No differences found.")
+	}
+}
+
+// This function will read the update zip in the given location.
+func readUpdateContent(updateFilePath string) (*updateContent, error) {
+	content := updateContent{
+		files: make(map[string]*updateFile),
+	}
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	hashAlgorithm := util.GetHashAlgorithm()
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return nil, err
+		}
+		relativePath := util.GetRelativePath(file)
+		switch relativePath {
+		case constant.UPDATE_DESCRIPTOR_V2_FILE:
+			if err = yaml.Unmarshal(data, &content.updateDescriptorV2); err != nil {
+				return nil, errors.New(fmt.Sprintf("'%s' is invalid. %v", constant.UPDATE_DESCRIPTOR_V2_FILE, err))
+			}
+		case constant.UPDATE_DESCRIPTOR_V3_FILE:
+			if err = yaml.Unmarshal(data, &content.updateDescriptorV3); err != nil {
+				return nil, errors.New(fmt.Sprintf("'%s' is invalid. %v", constant.UPDATE_DESCRIPTOR_V3_FILE, err))
+			}
+		}
+		checksum, err := util.GetChecksumOfData(data, hashAlgorithm)
+		if err != nil {
+			return nil, err
+		}
+		fileDetails := updateFile{
+			checksum: checksum,
+		}
+		if util.IsTextFile(relativePath) {
+			fileDetails.content = string(data)
+		}
+		content.files[relativePath] = &fileDetails
+	}
+	return &content, nil
+}
+
+// This function will print the differences of the fields of update descriptors. Returns true if there are
+// differences.
+func diffUpdateDescriptors(oldContent, newContent *updateContent) bool {
+	oldV3, newV3 := oldContent.updateDescriptorV3, newContent.updateDescriptorV3
+	changes := make([][]string, 0)
+	addFieldChange := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, []string{field, oldValue, newValue})
+		}
+	}
+	addFieldChange("update_number", oldV3.UpdateNumber, newV3.UpdateNumber)
+	addFieldChange("platform_name", oldV3.PlatformName, newV3.PlatformName)
+	addFieldChange("platform_version", oldV3.PlatformVersion, newV3.PlatformVersion)
+	addFieldChange("description", oldV3.Description, newV3.Description)
+	addFieldChange("instructions", oldV3.Instructions, newV3.Instructions)
+	addFieldChange("applies_to", oldContent.updateDescriptorV2.AppliesTo, newContent.updateDescriptorV2.AppliesTo)
+
+	// Compare bug fixes
+	bugFixKeys := getSortedKeys(oldV3.BugFixes, newV3.BugFixes)
+	for _, key := range bugFixKeys {
+		oldSummary, isInOld := oldV3.BugFixes[key]
+		newSummary, isInNew := newV3.BugFixes[key]
+		if !isInOld {
+			changes = append(changes, []string{"bug_fixes", "", key + ": " + newSummary})
+		} else if !isInNew {
+			changes = append(changes, []string{"bug_fixes", key + ": " + oldSummary, ""})
+		} else if oldSummary != newSummary {
+			changes = append(changes, []string{"bug_fixes", key + ": " + oldSummary, key + ": " + newSummary})
+		}
+	}
+
+	if len(changes) == 0 {
+		return false
+	}
+	util.PrintInBold("Update descriptor changes:\n")
+	for _, change := range changes {
+		fmt.Println(fmt.Sprintf("\t%s:", change[0]))
+		if len(change[1]) != 0 {
+			printPrefixedLines("\t\t- ", change[1])
+		}
+		if len(change[2]) != 0 {
+			printPrefixedLines("\t\t+ ", change[2])
+		}
+	}
+	fmt.Println()
+	return true
+}
+
+// This function will print the differences of the file lists of the given products. Returns true if there are
+// differences.
+func diffProducts(title string, oldProducts, newProducts []util.ProductChanges) bool {
+	oldProductsMap := make(map[string]util.ProductChanges)
+	for _, product := range oldProducts {
+		oldProductsMap[product.ProductName+"-"+product.ProductVersion] = product
+	}
+	newProductsMap := make(map[string]util.ProductChanges)
+	for _, product := range newProducts {
+		newProductsMap[product.ProductName+"-"+product.ProductVersion] = product
+	}
+
+	lines := make([]string, 0)
+	for _, productId := range getSortedProductKeys(oldProductsMap, newProductsMap) {
+		oldProduct, isInOld := oldProductsMap[productId]
+		newProduct, isInNew := newProductsMap[productId]
+		if !isInOld {
+			lines = append(lines, fmt.Sprintf("\t+ %s", productId))
+			continue
+		}
+		if !isInNew {
+			lines = append(lines, fmt.Sprintf("\t- %s", productId))
+			continue
+		}
+		productLines := make([]string, 0)
+		productLines = append(productLines, diffFileList("added_files", oldProduct.AddedFiles,
+			newProduct.AddedFiles)...)
+		productLines = append(productLines, diffFileList("modified_files", oldProduct.ModifiedFiles,
+			newProduct.ModifiedFiles)...)
+		productLines = append(productLines, diffFileList("removed_files", oldProduct.RemovedFiles,
+			newProduct.RemovedFiles)...)
+		if len(productLines) != 0 {
+			lines = append(lines, fmt.Sprintf("\t%s:", productId))
+			lines = append(lines, productLines...)
+		}
+	}
+	if len(lines) == 0 {
+		return false
+	}
+	util.PrintInBold(fmt.Sprintf("%s changes:\n", title))
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	fmt.Println()
+	return true
+}
+
+// This function will return the differences of the given file lists as printable lines.
+func diffFileList(listName string, oldFiles, newFiles []string) []string {
+	lines := make([]string, 0)
+	for _, file := range newFiles {
+		if !util.IsStringIsInSlice(file, oldFiles) {
+			lines = append(lines, fmt.Sprintf("\t\t+ %s", file))
+		}
+	}
+	for _, file := range oldFiles {
+		if !util.IsStringIsInSlice(file, newFiles) {
+			lines = append(lines, fmt.Sprintf("\t\t- %s", file))
+		}
+	}
+	if len(lines) == 0 {
+		return lines
+	}
+	return append([]string{fmt.Sprintf("\t    %s:", listName)}, lines...)
+}
+
+// This function will print the differences of the files in the updates. Returns true if there are differences.
+func diffFiles(oldFiles, newFiles map[string]*updateFile) bool {
+	allPaths := make(map[string]bool)
+	for filePath := range oldFiles {
+		allPaths[filePath] = true
+	}
+	for filePath := range newFiles {
+		allPaths[filePath] = true
+	}
+	sortedPaths := make([]string, 0)
+	for filePath := range allPaths {
+		sortedPaths = append(sortedPaths, filePath)
+	}
+	sort.Strings(sortedPaths)
+
+	hashAlgorithm := util.GetHashAlgorithm()
+	isChanged := false
+	diffs := make([]string, 0)
+	for _, filePath := range sortedPaths {
+		oldFile, isInOld := oldFiles[filePath]
+		newFile, isInNew := newFiles[filePath]
+		if !isChanged && (!isInOld || !isInNew || oldFile.checksum != newFile.checksum) {
+			util.PrintInBold("File changes:\n")
+			isChanged = true
+		}
+		switch {
+		case !isInOld:
+			fmt.Println(fmt.Sprintf("\tadded    %s (%s: %s)", filePath, hashAlgorithm, newFile.checksum))
+		case !isInNew:
+			fmt.Println(fmt.Sprintf("\tremoved  %s (%s: %s)", filePath, hashAlgorithm, oldFile.checksum))
+		case oldFile.checksum != newFile.checksum:
+			fmt.Println(fmt.Sprintf("\tmodified %s (%s: %s -> %s)", filePath, hashAlgorithm, oldFile.checksum,
+				newFile.checksum))
+			// Descriptors are already compared field by field
+			if util.IsTextFile(filePath) && filePath != constant.UPDATE_DESCRIPTOR_V3_FILE {
+				diffs = append(diffs, util.UnifiedDiff("old/"+filePath, "new/"+filePath,
+					util.SplitLines(oldFile.content), util.SplitLines(newFile.content), diffContextLines))
+			}
+		}
+	}
+	if len(diffs) != 0 {
+		fmt.Println()
+		for _, diff := range diffs {
+			fmt.Print(diff)
+		}
+	}
+	return isChanged
+}
+
+// This function will return the sorted union of keys of the given maps.
+func getSortedKeys(map1, map2 map[string]string) []string {
+	keys := make([]string, 0)
+	for key := range map1 {
+		keys = append(keys, key)
+	}
+	for key := range map2 {
+		if _, found := map1[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// This function will return the sorted union of product ids of the given maps.
+func getSortedProductKeys(map1, map2 map[string]util.ProductChanges) []string {
+	keys := make([]string, 0)
+	for key := range map1 {
+		keys = append(keys, key)
+	}
+	for key := range map2 {
+		if _, found := map1[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// This function will print each line of the given value with the given prefix.
+func printPrefixedLines(prefix, value string) {
+	for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+		fmt.Println(prefix + line)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadUpdateContent(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	updateZip := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-1234.zip")
+	createTestZip(t, updateZip, map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-1234/update-descriptor3.yaml": `update_number: 1234
+platform_version: 4.4.0
+platform_name: wilkes
+`,
+		"WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/repository/conf/a.xml":       "<a/>\n",
+		"WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/repository/components/b.jar": "binary",
+	})
+
+	content, err := readUpdateContent(updateZip)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if content.updateDescriptorV3.UpdateNumber != "1234" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "1234", content.updateDescriptorV3.UpdateNumber)
+	}
+	xmlFile, found := content.files["carbon.home/repository/conf/a.xml"]
+	if !found {
+		t.Fatalf("Test failed, carbon.home/repository/conf/a.xml not found in %v", content.files)
+	}
+	if xmlFile.content != "<a/>\n" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "<a/>\n", xmlFile.content)
+	}
+	jarFile, found := content.files["carbon.home/repository/components/b.jar"]
+	if !found {
+		t.Fatalf("Test failed, carbon.home/repository/components/b.jar not found in %v", content.files)
+	}
+	if len(jarFile.content) != 0 {
+		t.Errorf("Test failed, content of binary files should not be stored")
+	}
+}
+
+func TestDiffFileList(t *testing.T) {
+	lines := diffFileList("added_files", []string{"a", "b"}, []string{"b", "c"})
+	expected := []string{"\t    added_files:", "\t\t+ c", "\t\t- a"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Test failed, expected: %v, actual: %v", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Test failed, expected: %s, actual: %s", expected[i], lines[i])
+		}
+	}
+	if len(diffFileList("added_files", []string{"a"}, []string{"a"})) != 0 {
+		t.Errorf("Test failed, no lines expected for equal lists")
+	}
+}
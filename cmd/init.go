@@ -39,11 +39,18 @@ var (
 		  Password for 'user@wso2.com': my_Password
 
 		# Enter your WSO2 credentials as arguments.
-		  wum-uc init -u user@wso2.com -p my_Password`)
+		  wum-uc init -u user@wso2.com -p my_Password
+
+		# Use OAuth2 client credentials instead of a WSO2 account (ie. in CI builds).
+		# Client secret is read from the WUMUC_CLIENT_SECRET environment variable
+		# or prompted.
+		  wum-uc init --client-credentials --client-id my_client_id`)
 )
 
 var username string
 var password string
+var isClientCredentialsEnabled bool
+var clientID string
 
 // initCmd represents the init command.
 var initCmd = &cobra.Command{
@@ -62,12 +69,21 @@ func init() {
 	initCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
 	initCmd.Flags().StringVarP(&username, "username", "u", "", "Specify your email")
 	initCmd.Flags().StringVarP(&password, "password", "p", "", "Specify your password")
+	initCmd.Flags().BoolVar(&isClientCredentialsEnabled, "client-credentials", false, "Use OAuth2 client "+
+		"credentials instead of WSO2 credentials")
+	initCmd.Flags().StringVar(&clientID, "client-id", os.Getenv(constant.WUMUC_CLIENT_ID), "Specify the client id "+
+		"used with --client-credentials")
 
 }
 
 // Initialize WUM-UC with WSO2 credentials.
 func initializeInitCommand(cmd *cobra.Command, args []string) {
 	logger.Debug("[Init] called")
+	if isClientCredentialsEnabled {
+		util.InitWithClientCredentials(clientID, []byte(os.Getenv(constant.WUMUC_CLIENT_SECRET)))
+		fmt.Fprint(os.Stderr, constant.DONE_MSG)
+		return
+	}
 	util.Init(username, []byte(password))
-	fmt.Fprintln(os.Stderr, constant.DONE_MSG)
+	fmt.Fprint(os.Stderr, constant.DONE_MSG)
 }
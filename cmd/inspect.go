@@ -0,0 +1,337 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/renstrom/dedent"
+	"github.com/spf13/cobra"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+	"gopkg.in/yaml.v2"
+)
+
+// Values used to print help command.
+var (
+	inspectCmdUse       = "inspect <update_loc>"
+	inspectCmdShortDesc = "Display a summary of an update zip"
+	inspectCmdLongDesc  = dedent.Dedent(`
+		This command will display a summary of the given update zip without
+		extracting it. Details are read from the update-descriptor3.yaml in the
+		update. Updates without an update-descriptor3.yaml are summarised using
+		the update-descriptor.yaml and the instructions.txt.`)
+	inspectCmdExamples = dedent.Dedent(`
+		# Display the summary as text.
+		  wum-uc inspect WSO2-CARBON-UPDATE-4.4.0-1234.zip
+
+		# Display the summary as json.
+		  wum-uc inspect WSO2-CARBON-UPDATE-4.4.0-1234.zip --format json`)
+)
+
+// This struct is used to store the summary of an update zip.
+type updateSummary struct {
+	UpdateName                  string            `json:"update-name"`
+	UpdateNumber                string            `json:"update-number"`
+	PlatformName                string            `json:"platform-name"`
+	PlatformVersion             string            `json:"platform-version"`
+	AppliesTo                   string            `json:"applies-to,omitempty"`
+	Description                 string            `json:"description"`
+	Instructions                string            `json:"instructions"`
+	BugFixes                    map[string]string `json:"bug-fixes"`
+	CompatibleProducts          []productSummary  `json:"compatible-products"`
+	PartiallyApplicableProducts []productSummary  `json:"partially-applicable-products"`
+	ResourceFiles               []string          `json:"resource-files"`
+	IsSecurityUpdate            bool              `json:"is-security-update"`
+	SizeInBytes                 int64             `json:"size-in-bytes"`
+}
+
+// This struct is used to store the file changes of a product in the update summary.
+type productSummary struct {
+	ProductName        string   `json:"product-name"`
+	ProductVersion     string   `json:"product-version"`
+	AddedFilesCount    int      `json:"added-files-count"`
+	ModifiedFilesCount int      `json:"modified-files-count"`
+	RemovedFilesCount  int      `json:"removed-files-count"`
+	AddedFiles         []string `json:"added-files"`
+	ModifiedFiles      []string `json:"modified-files"`
+	RemovedFiles       []string `json:"removed-files"`
+}
+
+var inspectOutputFormat string
+
+// inspectCmd represents the inspect command.
+var inspectCmd = &cobra.Command{
+	Use:     inspectCmdUse,
+	Short:   inspectCmdShortDesc,
+	Long:    inspectCmdLongDesc,
+	Example: inspectCmdExamples,
+	Run:     initializeInspectCommand,
+}
+
+// This function will be called first and this will add flags to the command.
+func init() {
+	RootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
+	inspectCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
+	inspectCmd.Flags().StringVarP(&inspectOutputFormat, "format", "f", constant.OUTPUT_FORMAT_TEXT,
+		"Output format (text|json)")
+}
+
+// This function will be called when the inspect command is called.
+func initializeInspectCommand(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		util.HandleErrorAndExit(errors.New("invalid number of arguments. Run 'wum-uc inspect --help' to " +
+			"view help"))
+	}
+	if inspectOutputFormat != constant.OUTPUT_FORMAT_TEXT && inspectOutputFormat != constant.OUTPUT_FORMAT_JSON {
+		util.HandleErrorAndExit(errors.New(fmt.Sprintf("invalid output format '%s'. Supported formats are "+
+			"'%s' and '%s'", inspectOutputFormat, constant.OUTPUT_FORMAT_TEXT, constant.OUTPUT_FORMAT_JSON)))
+	}
+	startInspection(args[0])
+}
+
+// This function will start the inspection of the given update zip.
+func startInspection(updateFilePath string) {
+	setLogLevel()
+	logger.Debug("inspect command called")
+
+	// Checks whether the update has the zip extension
+	util.IsZipFile(constant.UPDATE, updateFilePath)
+
+	// Checks whether the update file exists
+	exists, err := util.IsFileExists(updateFilePath)
+	util.HandleErrorAndExit(err, "")
+	if !exists {
+		util.HandleErrorAndExit(errors.New(fmt.Sprintf("Entered update file does not exist at '%s'.",
+			updateFilePath)))
+	}
+
+	summary, err := readUpdateSummary(updateFilePath)
+	util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while reading '%s'.", updateFilePath))
+
+	if inspectOutputFormat == constant.OUTPUT_FORMAT_JSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		util.HandleErrorAndExit(err, "Error occurred while marshalling the update summary.")
+		fmt.Println(string(data))
+	} else {
+		printUpdateSummary(summary)
+	}
+}
+
+// This function will read the update zip in the given location and return the summary of the update.
+func readUpdateSummary(updateFilePath string) (*updateSummary, error) {
+	fileInfo, err := os.Stat(updateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	summary := updateSummary{
+		UpdateName:    strings.TrimSuffix(fileInfo.Name(), ".zip"),
+		SizeInBytes:   fileInfo.Size(),
+		ResourceFiles: make([]string, 0),
+	}
+
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	updateDescriptorV2 := util.UpdateDescriptorV2{}
+	updateDescriptorV3 := util.UpdateDescriptorV3{}
+	isUpdateDescriptorV3Found := false
+	instructions := ""
+	for _, file := range zipReader.Reader.File {
+		// Only the files in the root directory of the update are resource files
+		if file.FileInfo().IsDir() || strings.Count(strings.TrimSuffix(file.Name, "/"), "/") != 1 {
+			continue
+		}
+		name := getFileName(file.Name)
+		summary.ResourceFiles = append(summary.ResourceFiles, name)
+		switch name {
+		case constant.UPDATE_DESCRIPTOR_V2_FILE:
+			data, err := readZipEntry(file)
+			if err != nil {
+				return nil, err
+			}
+			if err = yaml.Unmarshal(data, &updateDescriptorV2); err != nil {
+				return nil, errors.New(fmt.Sprintf("'%s' is invalid. %v", constant.UPDATE_DESCRIPTOR_V2_FILE, err))
+			}
+		case constant.UPDATE_DESCRIPTOR_V3_FILE:
+			data, err := readZipEntry(file)
+			if err != nil {
+				return nil, err
+			}
+			if err = yaml.Unmarshal(data, &updateDescriptorV3); err != nil {
+				return nil, errors.New(fmt.Sprintf("'%s' is invalid. %v", constant.UPDATE_DESCRIPTOR_V3_FILE, err))
+			}
+			isUpdateDescriptorV3Found = true
+		case constant.LICENSE_FILE:
+			data, err := readZipEntry(file)
+			if err != nil {
+				return nil, err
+			}
+			summary.IsSecurityUpdate = strings.Contains(string(data), constant.SECURITY_UPDATE_LICENSE_TEXT)
+		case constant.INSTRUCTIONS_FILE:
+			data, err := readZipEntry(file)
+			if err != nil {
+				return nil, err
+			}
+			instructions = string(data)
+		}
+	}
+	sort.Strings(summary.ResourceFiles)
+
+	// update-descriptor3.yaml takes precedence over the update-descriptor.yaml
+	summary.AppliesTo = updateDescriptorV2.AppliesTo
+	if isUpdateDescriptorV3Found {
+		summary.UpdateNumber = updateDescriptorV3.UpdateNumber
+		summary.PlatformName = updateDescriptorV3.PlatformName
+		summary.PlatformVersion = updateDescriptorV3.PlatformVersion
+		summary.Description = updateDescriptorV3.Description
+		summary.Instructions = updateDescriptorV3.Instructions
+		summary.BugFixes = updateDescriptorV3.BugFixes
+		summary.CompatibleProducts = getProductSummaries(updateDescriptorV3.CompatibleProducts)
+		summary.PartiallyApplicableProducts = getProductSummaries(updateDescriptorV3.PartiallyApplicableProducts)
+		return &summary, nil
+	}
+	summary.UpdateNumber = updateDescriptorV2.UpdateNumber
+	summary.PlatformName = updateDescriptorV2.PlatformName
+	summary.PlatformVersion = updateDescriptorV2.PlatformVersion
+	summary.Description = updateDescriptorV2.Description
+	summary.Instructions = instructions
+	summary.BugFixes = updateDescriptorV2.BugFixes
+	// File changes of the update-descriptor.yaml are not specific to a product, so they are shown as the changes of
+	// the platform
+	productChanges := make([]util.ProductChanges, 0)
+	fileChanges := updateDescriptorV2.FileChanges
+	if len(fileChanges.AddedFiles)+len(fileChanges.ModifiedFiles)+len(fileChanges.RemovedFiles) > 0 {
+		productChanges = append(productChanges, util.ProductChanges{
+			ProductName:    updateDescriptorV2.PlatformName,
+			ProductVersion: updateDescriptorV2.PlatformVersion,
+			AddedFiles:     fileChanges.AddedFiles,
+			ModifiedFiles:  fileChanges.ModifiedFiles,
+			RemovedFiles:   fileChanges.RemovedFiles,
+		})
+	}
+	summary.CompatibleProducts = getProductSummaries(productChanges)
+	summary.PartiallyApplicableProducts = make([]productSummary, 0)
+	return &summary, nil
+}
+
+// This function will convert the given product changes to product summaries.
+func getProductSummaries(productChanges []util.ProductChanges) []productSummary {
+	productSummaries := make([]productSummary, 0)
+	for _, productChange := range productChanges {
+		productSummaries = append(productSummaries, productSummary{
+			ProductName:        productChange.ProductName,
+			ProductVersion:     productChange.ProductVersion,
+			AddedFilesCount:    len(productChange.AddedFiles),
+			ModifiedFilesCount: len(productChange.ModifiedFiles),
+			RemovedFilesCount:  len(productChange.RemovedFiles),
+			AddedFiles:         productChange.AddedFiles,
+			ModifiedFiles:      productChange.ModifiedFiles,
+			RemovedFiles:       productChange.RemovedFiles,
+		})
+	}
+	return productSummaries
+}
+
+// This function will read the content of the given zip entry.
+func readZipEntry(file *zip.File) ([]byte, error) {
+	zippedFile, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer zippedFile.Close()
+	return ioutil.ReadAll(zippedFile)
+}
+
+// This function will print the given update summary as text.
+func printUpdateSummary(summary *updateSummary) {
+	util.PrintInBold(fmt.Sprintf("Update name       : %s\n", summary.UpdateName))
+	fmt.Println(fmt.Sprintf("Update number     : %s", summary.UpdateNumber))
+	fmt.Println(fmt.Sprintf("Platform          : %s %s", summary.PlatformName, summary.PlatformVersion))
+	if len(summary.AppliesTo) != 0 {
+		fmt.Println(fmt.Sprintf("Applies to        : %s", summary.AppliesTo))
+	}
+	fmt.Println(fmt.Sprintf("Size              : %d bytes", summary.SizeInBytes))
+	securityUpdate := "no"
+	if summary.IsSecurityUpdate {
+		securityUpdate = "yes"
+	}
+	fmt.Println(fmt.Sprintf("Security update   : %s", securityUpdate))
+	fmt.Println(fmt.Sprintf("Resource files    : %s", strings.Join(summary.ResourceFiles, ", ")))
+
+	util.PrintInBold("\nBug fixes:\n")
+	bugFixKeys := make([]string, 0)
+	for key := range summary.BugFixes {
+		bugFixKeys = append(bugFixKeys, key)
+	}
+	sort.Strings(bugFixKeys)
+	for _, key := range bugFixKeys {
+		fmt.Println(fmt.Sprintf("\t%s: %s", key, summary.BugFixes[key]))
+	}
+	util.PrintInBold("\nDescription:\n")
+	printIndented(summary.Description)
+	util.PrintInBold("\nInstructions:\n")
+	printIndented(summary.Instructions)
+
+	util.PrintInBold("\nCompatible products:\n")
+	printProductSummaries(summary.CompatibleProducts)
+	util.PrintInBold("\nPartially applicable products:\n")
+	printProductSummaries(summary.PartiallyApplicableProducts)
+}
+
+// This function will print the file changes of the given product summaries.
+func printProductSummaries(productSummaries []productSummary) {
+	if len(productSummaries) == 0 {
+		fmt.Println("\tN/A")
+		return
+	}
+	for _, product := range productSummaries {
+		fmt.Println(fmt.Sprintf("\t%s-%s: %d added, %d modified, %d removed", product.ProductName,
+			product.ProductVersion, product.AddedFilesCount, product.ModifiedFilesCount, product.RemovedFilesCount))
+		printFileList("added", product.AddedFiles)
+		printFileList("modified", product.ModifiedFiles)
+		printFileList("removed", product.RemovedFiles)
+	}
+}
+
+// This function will print the given list of files with the given label.
+func printFileList(label string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Println(fmt.Sprintf("\t\t%s:", label))
+	for _, file := range files {
+		fmt.Println(fmt.Sprintf("\t\t\t%s", file))
+	}
+}
+
+// This function will print the given multi-line string with a tab at the beginning of each line.
+func printIndented(data string) {
+	for _, line := range strings.Split(strings.TrimRight(data, "\n"), "\n") {
+		fmt.Println("\t" + line)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// This function creates a zip file in the given location with the given entries. Key of the entries map is the name
+// of the entry and the value is the content.
+func createTestZip(t *testing.T, location string, entries map[string]string) {
+	zipFile, err := os.Create(location)
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating %s: %v", location, err)
+	}
+	defer zipFile.Close()
+	writer := zip.NewWriter(zipFile)
+	names := make([]string, 0)
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Test failed, error occurred while creating %s: %v", name, err)
+		}
+		entry.Write([]byte(entries[name]))
+	}
+	if err = writer.Close(); err != nil {
+		t.Fatalf("Test failed, error occurred while closing %s: %v", location, err)
+	}
+}
+
+func TestReadUpdateSummary(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	updateZip := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-1234.zip")
+	createTestZip(t, updateZip, map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-1234/LICENSE.txt": "Licensed under Apache License 2.0",
+		"WSO2-CARBON-UPDATE-4.4.0-1234/update-descriptor3.yaml": `update_number: 1234
+platform_version: 4.4.0
+platform_name: wilkes
+description: Fix the issue
+bug_fixes:
+  ABC-1: Issue summary
+compatible_products:
+- product_name: wso2am
+  product_version: 2.1.0.1
+  added_files:
+  - repository/conf/a.xml
+  modified_files:
+  - repository/conf/b.xml
+  - repository/conf/c.xml
+`,
+		"WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/repository/conf/a.xml": "a",
+	})
+
+	summary, err := readUpdateSummary(updateZip)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if summary.UpdateNumber != "1234" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "1234", summary.UpdateNumber)
+	}
+	if summary.PlatformName != "wilkes" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "wilkes", summary.PlatformName)
+	}
+	if !summary.IsSecurityUpdate {
+		t.Errorf("Test failed, expected: %v, actual: %v", true, summary.IsSecurityUpdate)
+	}
+	if len(summary.ResourceFiles) != 2 {
+		t.Errorf("Test failed, expected: %d, actual: %d", 2, len(summary.ResourceFiles))
+	}
+	if len(summary.CompatibleProducts) != 1 {
+		t.Fatalf("Test failed, expected: %d, actual: %d", 1, len(summary.CompatibleProducts))
+	}
+	product := summary.CompatibleProducts[0]
+	if product.AddedFilesCount != 1 || product.ModifiedFilesCount != 2 || product.RemovedFilesCount != 0 {
+		t.Errorf("Test failed, unexpected file counts: %v", product)
+	}
+}
+
+func TestReadUpdateSummaryWithoutUpdateDescriptorV3(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	updateZip := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-0001.zip")
+	createTestZip(t, updateZip, map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-0001/instructions.txt": "Restart the server",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/update-descriptor.yaml": `update_number: "0001"
+platform_version: 4.4.0
+platform_name: wilkes
+applies_to: All the products based on carbon 4.4.0
+description: Fix the issue
+file_changes:
+  added_files:
+  - repository/conf/a.xml
+  modified_files:
+  - repository/conf/b.xml
+  removed_files: []
+`,
+	})
+
+	summary, err := readUpdateSummary(updateZip)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if summary.UpdateNumber != "0001" || summary.Instructions != "Restart the server" {
+		t.Errorf("Test failed, unexpected summary: %v", summary)
+	}
+	if len(summary.CompatibleProducts) != 1 {
+		t.Fatalf("Test failed, expected: %d, actual: %d", 1, len(summary.CompatibleProducts))
+	}
+	product := summary.CompatibleProducts[0]
+	if product.ProductName != "wilkes" || product.AddedFilesCount != 1 || product.ModifiedFilesCount != 1 ||
+		product.RemovedFilesCount != 0 {
+		t.Errorf("Test failed, unexpected file changes: %v", product)
+	}
+}
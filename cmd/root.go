@@ -31,6 +31,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -44,10 +45,17 @@ var (
 
 	isDebugLogsEnabled = false
 	isTraceLogsEnabled = false
+
+	// Used to continue even if secrets are found in the update and to provide the allowlist of known false positives
+	isSecretsAllowed     = false
+	secretsAllowlistPath = ""
 )
 
 var cfgFile string
 
+// Name of the profile in the config.yaml which should be used
+var profileName string
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   "wum-uc",
@@ -65,6 +73,12 @@ func Execute() {
 
 func init() {
 	cobra.OnInitialize(setLogLevel, checkPrerequisites, initConfig, checkWUMUCVersion)
+
+	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file which contains the settings "+
+		"(default is config.yaml in the current directory or $WUM_UC_HOME)")
+	RootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Name of the profile in the config.yaml "+
+		"to be used (overrides the "+constant.WUMUC_PROFILE+" environment variable)")
+	viper.BindPFlag(constant.PROFILE, RootCmd.PersistentFlags().Lookup("profile"))
 }
 
 // This function checks the existence of prerequisite programs needed for running 'wum-uc' tool.
@@ -90,11 +104,6 @@ func isSVNCommandAvailableInPath() (bool, error) {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
-		// enable ability to specify config file via flag
-		viper.SetConfigFile(cfgFile)
-	}
-
 	setDefaultValues()
 
 	// Check whether the user has specified the WUM_UC_HOME environment variable.
@@ -111,12 +120,22 @@ func initConfig() {
 		util.SetWUMUCLocalRepo(WUMUCHome)
 	}
 	viper.Set(constant.WUM_UC_HOME, WUMUCHome)
-	util.LoadWUMUCConfig(WUMUCHome)
 
-	viper.SetConfigName("config") // name of config file (without extension)
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("$HOME/.wum-uc")
-	//viper.AutomaticEnv()
+	if cfgFile != "" {
+		// enable ability to specify config file via flag. Config file is not searched in the default locations as
+		// setting the config name clears the config file set here.
+		viper.SetConfigFile(cfgFile)
+	} else {
+		viper.SetConfigName("config") // name of config file (without extension)
+		viper.AddConfigPath(".")
+		viper.AddConfigPath(WUMUCHome)
+		viper.AddConfigPath("$HOME/.wum-uc")
+	}
+	// Every key can be overridden using an environment variable, ie. RESOURCE_FILES.SKIP using
+	// WUMUC_RESOURCE_FILES_SKIP
+	viper.SetEnvPrefix(constant.WUMUC_ENV_PREFIX)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
@@ -124,10 +143,16 @@ func initConfig() {
 	} else {
 		logger.Debug("Config file not found.")
 	}
+	// Loaded after reading the config file, so that the configured credential store is used
+	util.LoadWUMUCConfig(WUMUCHome)
 
 	logger.Debug(fmt.Sprintf("PATH_SEPARATOR: %s", constant.PATH_SEPARATOR))
 	logger.Debug("Config Values: ---------------------------")
 	logger.Debug(fmt.Sprintf("%s: %s", constant.CHECK_MD5_DISABLED, viper.GetString(constant.CHECK_MD5_DISABLED)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.HASH_ALGORITHM, viper.GetString(constant.HASH_ALGORITHM)))
+	logger.Debug(fmt.Sprintf("%s: %v", constant.DETERMINISTIC_ZIP, viper.GetBool(constant.DETERMINISTIC_ZIP)))
+	logger.Debug(fmt.Sprintf("%s: %v", constant.RESOLVE_SYMLINKS, viper.GetBool(constant.RESOLVE_SYMLINKS)))
+	logger.Debug(fmt.Sprintf("%s: %v", constant.LINT_RULES, viper.Get(constant.LINT_RULES)))
 	logger.Debug(fmt.Sprintf("%s: %s", constant.RESOURCE_FILES_MANDATORY,
 		viper.GetStringSlice(constant.RESOURCE_FILES_MANDATORY)))
 	logger.Debug(fmt.Sprintf("%s: %s", constant.RESOURCE_FILES_OPTIONAL,
@@ -136,6 +161,15 @@ func initConfig() {
 		viper.GetStringSlice(constant.RESOURCE_FILES_SKIP)))
 	logger.Debug(fmt.Sprintf("%s: %s", constant.PLATFORM_VERSIONS,
 		viper.GetStringMapString(constant.PLATFORM_VERSIONS)))
+	logger.Debug(fmt.Sprintf("%s: %v", constant.PLATFORMS, viper.Get(constant.PLATFORMS)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.PLATFORMS_URL, viper.GetString(constant.PLATFORMS_URL)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.PRODUCT_CATALOG, viper.GetString(constant.PRODUCT_CATALOG)))
+	logger.Debug(fmt.Sprintf("%s: %v", constant.HTTP_CLIENT, viper.GetStringMap(constant.HTTP_CLIENT)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.CREDENTIALS_HELPER, viper.GetString(constant.CREDENTIALS_HELPER)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.CREDENTIALS_KEY_FILE, viper.GetString(constant.CREDENTIALS_KEY_FILE)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.PROFILE, viper.GetString(constant.PROFILE)))
+	logger.Debug(fmt.Sprintf("%s: %s", constant.MAX_CLASS_VERSIONS,
+		viper.GetStringMapString(constant.MAX_CLASS_VERSIONS)))
 	logger.Debug("-----------------------------------------")
 }
 
@@ -164,11 +198,29 @@ func setDefaultValues() {
 	viper.SetDefault(constant.RESOURCE_FILES_MANDATORY, util.ResourceFiles_Mandatory)
 	viper.SetDefault(constant.RESOURCE_FILES_OPTIONAL, util.ResourceFiles_Optional)
 	viper.SetDefault(constant.RESOURCE_FILES_SKIP, util.ResourceFiles_Skip)
-	viper.SetDefault(constant.PLATFORM_VERSIONS, util.PlatformVersions)
+	viper.SetDefault(constant.PLATFORMS, util.Platforms)
+	viper.SetDefault(constant.HTTP_CLIENT_TIMEOUT_IN_SECONDS, util.HTTPClientTimeoutInSeconds)
+	viper.SetDefault(constant.HTTP_CLIENT_MAX_RETRIES, util.HTTPClientMaxRetries)
+	viper.SetDefault(constant.HTTP_CLIENT_INITIAL_BACKOFF_IN_MILLISECONDS, util.HTTPClientInitialBackoffInMilliseconds)
+	viper.SetDefault(constant.HTTP_CLIENT_MAX_BACKOFF_IN_SECONDS, util.HTTPClientMaxBackoffInSeconds)
+	viper.SetDefault(constant.MAX_CLASS_VERSIONS, util.MaxClassVersions)
+	viper.SetDefault(constant.HASH_ALGORITHM, util.HashAlgorithm)
+	viper.SetDefault(constant.DETERMINISTIC_ZIP, util.DeterministicZip)
+	viper.SetDefault(constant.LINT_RULES, util.LintRules)
 }
 
 // This function checks whether the current version of 'wum-uc' still being supported for creating wum updates.
 func checkWUMUCVersion() {
+	if isOfflineEnabled {
+		logger.Debug("wum-uc version check skipped in the offline mode")
+		return
+	}
+	// Mock WUM server, the stored tokens and the configuration should be usable without connecting to the WUM servers
+	if command, _, err := RootCmd.Find(os.Args[1:]); err == nil && (command == devServerCmd ||
+		command == authCmd || command.Parent() == authCmd || command == configCmd || command.Parent() == configCmd) {
+		logger.Debug(fmt.Sprintf("wum-uc version check skipped for the %s command", command.Name()))
+		return
+	}
 	logger.Debug("wum-uc version check started")
 	// Check if last update check timestamp is older than one day.
 	wumucUpdateTimestampFilePath := filepath.Join(WUMUCHome, constant.WUMUC_CACHE_DIRECTORY, constant.WUMUC_UPDATE_CHECK_TIMESTAMP_FILENAME)
@@ -215,7 +267,10 @@ func checkWithWUMUCAdmin() {
 	apiURL := util.GetWUMUCConfigs().VersionURL + "/" + constant.WUMUCADMIN_API_CONTEXT + "/" + constant.
 		VERSION + "/" + Version
 
-	response := util.InvokeGetRequest(apiURL)
+	response, err := util.InvokeGetRequest(apiURL)
+	if err != nil {
+		util.HandleUnableToConnectErrorAndExit(err)
+	}
 	versionResponse := util.VersionResponse{}
 	util.ProcessResponseFromServer(response, &versionResponse)
 	// Exit if the current version is no longer supported for creating updates
@@ -234,7 +289,7 @@ func checkWithWUMUCAdmin() {
 	utcTime := time.Now().UTC().Unix()
 	logger.Debug(fmt.Sprintf("Current timestamp  %v", utcTime))
 	cacheDirectoryPath := filepath.Join(WUMUCHome, constant.WUMUC_CACHE_DIRECTORY)
-	err := util.CreateDirectory(cacheDirectoryPath)
+	err = util.CreateDirectory(cacheDirectoryPath)
 	if err != nil {
 		logger.Error(fmt.Sprintf("%v error occured in creating the directory %s for saving %s cache file", err,
 			cacheDirectoryPath, constant.WUMUC_UPDATE_CHECK_TIMESTAMP_FILENAME))
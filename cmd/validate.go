@@ -16,13 +16,15 @@ package cmd
 
 import (
 	"archive/zip"
-	"crypto/md5"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/renstrom/dedent"
@@ -40,8 +42,36 @@ var (
 		This command will validate the given update zip. Files will be
 		matched against the given distribution. This will also validate
 		the structure of the update-descriptor.yaml and update-descrjptor3.yaml files as well.
-		Please set LICENSE_MD5 environment variable to the expected
-		md5 value of the LICENSE.txt file.`)
+		Please set LICENSE_SHA256 environment variable to the expected
+		checksum of the LICENSE.txt file. LICENSE_MD5 is only accepted
+		if HASH_ALGORITHM is md5. Project configuration is only used if
+		the update directory is given using --project-config.`)
+)
+
+// Update directory of which the project configuration is used
+var projectConfigDirectory string
+
+// This struct is used to store the locations of the expected checksums of a resource file.
+type resourceChecksumSource struct {
+	sha256EnvName string
+	sha256Url     string
+	md5EnvName    string
+	md5Url        string
+}
+
+var (
+	licenseChecksumSource = resourceChecksumSource{
+		sha256EnvName: constant.LICENSE_SHA256,
+		sha256Url:     constant.LICENSE_SHA256_URL,
+		md5EnvName:    constant.LICENSE_MD5,
+		md5Url:        constant.LICENSE_MD5_URL,
+	}
+	notAContributionChecksumSource = resourceChecksumSource{
+		sha256EnvName: constant.NOT_A_CONTRIBUTION_SHA256,
+		sha256Url:     constant.NOT_A_CONTRIBUTION_SHA256_URL,
+		md5EnvName:    constant.NOT_A_CONTRIBUTION_MD5,
+		md5Url:        constant.NOT_A_CONTRIBUTION_MD5_URL,
+	}
 )
 
 // ValidateCmd represents the validate command
@@ -58,6 +88,14 @@ func init() {
 
 	validateCmd.Flags().BoolVarP(&isDebugLogsEnabled, "debug", "d", util.EnableDebugLogs, "Enable debug logs")
 	validateCmd.Flags().BoolVarP(&isTraceLogsEnabled, "trace", "t", util.EnableTraceLogs, "Enable trace logs")
+	validateCmd.Flags().BoolVar(&isSecretsAllowed, "allow-secrets", false, "Continue even if secrets are found "+
+		"in the update")
+	validateCmd.Flags().StringVar(&secretsAllowlistPath, "secrets-allowlist", "", "Location of the allowlist of "+
+		"known false positives of the secret scan")
+	validateCmd.Flags().BoolVar(&isOfflineEnabled, "offline", false, "Validate the resource files using the "+
+		"product catalog instead of downloading their checksums and skip the wum-uc version check")
+	validateCmd.Flags().StringVar(&projectConfigDirectory, "project-config", "", "Update directory of which the "+
+		constant.PROJECT_CONFIG_FILE+" files are used to check the pinned update details")
 }
 
 // This function will be called when the validate command is called.
@@ -66,6 +104,13 @@ func initializeValidateCommand(cmd *cobra.Command, args []string) {
 		util.HandleErrorAndExit(errors.New("invalid number of arguments. Run 'wum-uc validate --help' to " +
 			"view help"))
 	}
+	// Project configuration is not discovered from the location of the update zip, as whoever controls that
+	// directory could change the configuration used to validate the update
+	if len(projectConfigDirectory) > 0 {
+		updateDirectoryPath, err := filepath.Abs(projectConfigDirectory)
+		util.HandleErrorAndExit(err, "Error occurred while getting the absolute path of the update directory")
+		loadProjectConfig(updateDirectoryPath)
+	}
 	startValidation(args[0], args[1])
 }
 
@@ -111,10 +156,11 @@ func startValidation(updateFilePath, distributionLocation string) {
 	// Checks update filename
 	locationInfo, err := os.Stat(updateFilePath)
 	util.HandleErrorAndExit(err, "Error occurred while getting the information of update file")
-	match, err := regexp.MatchString(constant.FILENAME_REGEX, locationInfo.Name())
+	fileNameRegex := util.GetProjectConfig().GetFileNameRegex()
+	match, err := regexp.MatchString(fileNameRegex, locationInfo.Name())
 	if !match {
 		util.HandleErrorAndExit(errors.New(fmt.Sprintf("Update filename '%s' does not match '%s' regular "+
-			"expression.", locationInfo.Name(), constant.FILENAME_REGEX)))
+			"expression.", locationInfo.Name(), fileNameRegex)))
 	}
 
 	// Sets the update name in viper configs
@@ -131,14 +177,58 @@ func startValidation(updateFilePath, distributionLocation string) {
 	util.HandleErrorAndExit(err)
 	logger.Trace(fmt.Sprintf("distributionFileMap: %v\n", distributionFileMap))
 
+	// Applies the lint rules to the update descriptor and the resource files
+	err = lintUpdate(updateFilePath, updateDescriptorV3)
+	util.HandleErrorAndExit(err)
+
 	// Compares the update with the provided distribution only if update-descriptor3.yaml exists
 	if updateDescriptorV3.UpdateNumber != "" {
+		err = checkPinnedUpdateDetails(updateDescriptorV3)
+		util.HandleErrorAndExit(err)
 		err = compare(updateFileMap, distributionFileMap, updateDescriptorV3)
 		util.HandleErrorAndExit(err)
 	}
+
+	// Verifies the checksums of all the files in the update
+	err = verifyChecksumManifest(updateFilePath, updateDescriptorV3)
+	util.HandleErrorAndExit(err)
+
+	// Scans the update for secrets
+	findings, err := scanUpdateForSecrets(updateFilePath)
+	util.HandleErrorAndExit(err)
+	checkSecretFindings(findings)
+
+	// Verifies the signature of the update
+	err = verifyUpdateSignature(updateFilePath)
+	util.HandleErrorAndExit(err)
+
+	// Reports the changed entries of the jars modified by the update and checks their class versions
+	updatedJars, err := readUpdatedJars(updateFilePath)
+	util.HandleErrorAndExit(err)
+	err = reportJarEntryChanges(updatedJars, distributionLocation)
+	util.HandleErrorAndExit(err)
+	err = checkClassVersions(updatedJars, distributionLocation, updateDescriptorV3.PlatformVersion)
+	util.HandleErrorAndExit(err)
 	fmt.Println("'" + updateName + "' validation successfully finished.")
 }
 
+// This function checks whether the update number and the platform version of the update match the values pinned in
+// the project configuration.
+func checkPinnedUpdateDetails(updateDescriptorV3 *util.UpdateDescriptorV3) error {
+	projectConfig := util.GetProjectConfig()
+	if len(projectConfig.UpdateNumber) > 0 && projectConfig.UpdateNumber != updateDescriptorV3.UpdateNumber {
+		return errors.New(fmt.Sprintf("update number '%s' in the %s does not match '%s' pinned in the %s",
+			updateDescriptorV3.UpdateNumber, constant.UPDATE_DESCRIPTOR_V3_FILE, projectConfig.UpdateNumber,
+			constant.PROJECT_CONFIG_FILE))
+	}
+	if len(projectConfig.PlatformVersion) > 0 && projectConfig.PlatformVersion != updateDescriptorV3.PlatformVersion {
+		return errors.New(fmt.Sprintf("platform version '%s' in the %s does not match '%s' pinned in the %s",
+			updateDescriptorV3.PlatformVersion, constant.UPDATE_DESCRIPTOR_V3_FILE, projectConfig.PlatformVersion,
+			constant.PROJECT_CONFIG_FILE))
+	}
+	return nil
+}
+
 // This function compares the files in the update and the provided distribution.
 func compare(updateFileMap, distributionFileMap map[string]bool, updateDescriptorV3 *util.UpdateDescriptorV3) error {
 	updateName := viper.GetString(constant.UPDATE_NAME)
@@ -170,6 +260,329 @@ func compare(updateFileMap, distributionFileMap map[string]bool, updateDescripto
 	return nil
 }
 
+// This function will verify the checksums of all the files in the update zip at the given location against the
+// checksums.yaml in the update. Updates which have a SHA-256 checksum in the update-descriptor3.yaml are created with a
+// checksums.yaml, so it is an error if it is missing. For legacy updates, only a warning is printed.
+func verifyChecksumManifest(updateFilePath string, updateDescriptorV3 *util.UpdateDescriptorV3) error {
+	updateName := viper.GetString(constant.UPDATE_NAME)
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+
+	var manifest *util.ChecksumManifest
+	actualChecksums := make(map[string]util.FileChecksum)
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return err
+		}
+		relativePath := strings.TrimPrefix(file.Name, updateName+"/")
+		if relativePath == constant.CHECKSUMS_FILE {
+			manifest = &util.ChecksumManifest{}
+			if err = yaml.Unmarshal(data, manifest); err != nil {
+				return errors.New(fmt.Sprintf("'%s' is invalid. %v", constant.CHECKSUMS_FILE, err))
+			}
+			continue
+		}
+		actualChecksums[relativePath] = util.NewFileChecksum(data)
+	}
+	if manifest == nil && len(updateDescriptorV3.Sha256sum) > 0 {
+		return errors.New(fmt.Sprintf("'%s' not found in the update. It is created with every update which has "+
+			"a sha256sum in the '%s'.", constant.CHECKSUMS_FILE, constant.UPDATE_DESCRIPTOR_V3_FILE))
+	}
+	if manifest == nil {
+		util.PrintWarning(fmt.Sprintf("'%s' not found in the update. Checksums of the files are not verified.",
+			constant.CHECKSUMS_FILE))
+		return nil
+	}
+	return util.VerifyChecksumManifest(manifest, actualChecksums)
+}
+
+// This function will apply the configured lint rules to the fields of the given update descriptor and the resource
+// files in the root of the update zip.
+func lintUpdate(updateFilePath string, updateDescriptorV3 *util.UpdateDescriptorV3) error {
+	rules, err := util.GetLintRules()
+	if err != nil {
+		return err
+	}
+	lintContext := util.NewLintContext(updateDescriptorV3)
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+
+	updateName := viper.GetString(constant.UPDATE_NAME)
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() || path.Dir(file.Name) != updateName {
+			continue
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return err
+		}
+		lintContext.AddFile(file.FileInfo().Name(), data)
+	}
+	violations, err := util.ApplyLintRules(rules, lintContext)
+	if err != nil {
+		return err
+	}
+	return util.ReportLintViolations(violations)
+}
+
+// This function will scan the files in the carbon.home directory of the update zip for secrets. File paths of the
+// findings are relative to the update root.
+func scanUpdateForSecrets(updateFilePath string) ([]util.SecretFinding, error) {
+	findings := make([]util.SecretFinding, 0)
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		// Remove the update name from the path. Names of the zip entries are always separated by '/'
+		relativePath := file.Name[strings.Index(file.Name, "/")+1:]
+		if !strings.HasPrefix(relativePath, constant.CARBON_HOME+"/") {
+			continue
+		}
+		fileFindings, err := scanZipEntryForSecrets(file, relativePath)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+	return findings, nil
+}
+
+// This function will scan the given zip entry for secrets.
+func scanZipEntryForSecrets(file *zip.File, relativePath string) ([]util.SecretFinding, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return util.ScanReaderForSecrets(relativePath, reader)
+}
+
+// This function will verify the detached signature of the update zip at the given location using the trusted keys
+// configured in the config.yaml. If trusted keys are configured, updates which are not signed are rejected.
+func verifyUpdateSignature(updateFilePath string) error {
+	signatureFilePath := util.GetSignatureFilePath(updateFilePath)
+	exists, err := util.IsFileExists(signatureFilePath)
+	if err != nil {
+		return err
+	}
+	trustedKeys := util.GetWUMUCConfigs().TrustedKeys
+	if !exists {
+		if len(trustedKeys) != 0 {
+			return errors.New(fmt.Sprintf("signature of the update not found at '%s'. Updates should be signed "+
+				"when trusted keys are configured in the %s", signatureFilePath, constant.WUMUC_CONFIG_FILE))
+		}
+		return nil
+	}
+	if len(trustedKeys) == 0 {
+		util.PrintWarning(fmt.Sprintf("Trusted keys are not configured in the %s. Signature of the update is not "+
+			"verified.", constant.WUMUC_CONFIG_FILE))
+		return nil
+	}
+	trustedKey, err := util.VerifyFileSignature(updateFilePath, signatureFilePath, trustedKeys)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("Signature of the update verified using '%s'.", trustedKey))
+	return nil
+}
+
+// This function will read the jars in the update zip at the given location. Key of the returned map is the path of the
+// jar relative to the carbon.home directory and the value is the content of the jar.
+func readUpdatedJars(updateFilePath string) (map[string][]byte, error) {
+	updateName := viper.GetString(constant.UPDATE_NAME)
+	// Names of the zip entries always use '/' as the separator
+	prefix := path.Join(updateName, constant.CARBON_HOME) + "/"
+	updatedJars := make(map[string][]byte)
+	zipReader, err := zip.OpenReader(updateFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() || !strings.HasPrefix(file.Name, prefix) ||
+			!strings.HasSuffix(file.Name, constant.JAR_EXTENSION) {
+			continue
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return nil, err
+		}
+		updatedJars[strings.TrimPrefix(file.Name, prefix)] = data
+	}
+	return updatedJars, nil
+}
+
+// This function compares the given jars of the update with the jars in the same locations of the distribution and
+// prints the added, removed and modified entries of each jar.
+func reportJarEntryChanges(updatedJars map[string][]byte, distributionLocation string) error {
+	if len(updatedJars) == 0 {
+		return nil
+	}
+	changesMap := make(map[string]*util.JarEntryChanges)
+	zipReader, err := zip.OpenReader(distributionLocation)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		relativePath := util.GetRelativePath(file)
+		updatedJar, found := updatedJars[relativePath]
+		if !found {
+			continue
+		}
+		newEntries, err := util.GetJarEntries(updatedJar)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error occurred while reading the entries of '%s'. %v", relativePath, err))
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return err
+		}
+		oldEntries, err := util.GetJarEntries(data)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Error occurred while reading the entries of '%s': %v", file.Name, err))
+			continue
+		}
+		changesMap[relativePath] = util.CompareJarEntries(oldEntries, newEntries)
+	}
+
+	modifiedJars := make([]string, 0)
+	for relativePath := range changesMap {
+		modifiedJars = append(modifiedJars, relativePath)
+	}
+	sort.Strings(modifiedJars)
+	for _, relativePath := range modifiedJars {
+		util.PrintJarEntryChanges(relativePath, changesMap[relativePath])
+	}
+	return nil
+}
+
+// This function checks whether the classes in the given jars of the update are compiled for a Java version which is
+// supported by the platform. The maximum supported class version is read from the configurations. If it is not
+// configured for the platform version, the highest class version found in the distribution is used.
+func checkClassVersions(updatedJars map[string][]byte, distributionLocation, platformVersion string) error {
+	if len(updatedJars) == 0 {
+		return nil
+	}
+	maxClassVersion := 0
+	configuredVersion, isConfigured := viper.GetStringMapString(constant.MAX_CLASS_VERSIONS)[platformVersion]
+	if isConfigured {
+		version, err := strconv.Atoi(configuredVersion)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Invalid value '%s' found in '%s' for '%s' platform version.",
+				configuredVersion, constant.MAX_CLASS_VERSIONS, platformVersion))
+		}
+		maxClassVersion = version
+		logger.Debug(fmt.Sprintf("Configured maximum class version of %s: %d", platformVersion, maxClassVersion))
+	} else {
+		version, err := getMaxClassVersionOfDistribution(distributionLocation)
+		if err != nil {
+			return err
+		}
+		maxClassVersion = version
+		logger.Debug(fmt.Sprintf("Maximum class version found in the distribution: %d", maxClassVersion))
+	}
+	if maxClassVersion == 0 {
+		logger.Debug("Maximum class version not found. Skipping the class version check.")
+		return nil
+	}
+
+	incompatibleJars := make([]string, 0)
+	for relativePath, data := range updatedJars {
+		classVersion, className, err := util.GetMaxClassVersion(data)
+		if err != nil {
+			return errors.New(fmt.Sprintf("Error occurred while reading the classes of '%s'. %v", relativePath, err))
+		}
+		if classVersion > maxClassVersion {
+			incompatibleJars = append(incompatibleJars, fmt.Sprintf("'%s' contains classes compiled for Java %s "+
+				"(ie. '%s' has class version %d)", relativePath, util.GetJavaVersion(classVersion), className,
+				classVersion))
+		}
+	}
+	if len(incompatibleJars) == 0 {
+		return nil
+	}
+	sort.Strings(incompatibleJars)
+	return errors.New(fmt.Sprintf("Following jars will fail with 'UnsupportedClassVersionError' as the highest "+
+		"supported class version is %d (Java %s).\n\t%s", maxClassVersion, util.GetJavaVersion(maxClassVersion),
+		strings.Join(incompatibleJars, "\n\t")))
+}
+
+// This function returns the highest class version of the classes in the jars of the given distribution. Reading all
+// the jars of a distribution takes time, so the version is cached in $WUMUC_HOME using the checksum of the
+// distribution.
+func getMaxClassVersionOfDistribution(distributionLocation string) (int, error) {
+	checksum, err := util.GetChecksum(distributionLocation, constant.HASH_ALGORITHM_SHA256)
+	if err != nil {
+		return 0, err
+	}
+	cacheFilePath := ""
+	if wumucHome := viper.GetString(constant.WUM_UC_HOME); len(wumucHome) > 0 {
+		cacheFilePath = filepath.Join(wumucHome, constant.WUMUC_CACHE_DIRECTORY, constant.CLASS_VERSION_CACHE_FILE)
+	}
+	cache := util.LoadClassVersionCache(cacheFilePath)
+	if maxClassVersion, found := cache.Get(checksum); found {
+		logger.Debug(fmt.Sprintf("Maximum class version of '%s' found in the cache", distributionLocation))
+		return maxClassVersion, nil
+	}
+	maxClassVersion, err := readMaxClassVersionOfDistribution(distributionLocation)
+	if err != nil {
+		return 0, err
+	}
+	if err = cache.Put(checksum, maxClassVersion); err != nil {
+		logger.Debug(fmt.Sprintf("Error occurred while caching the maximum class version of '%s': %v",
+			distributionLocation, err))
+	}
+	return maxClassVersion, nil
+}
+
+// This function will read the classes in all the jars of the given distribution and return the highest class version.
+func readMaxClassVersionOfDistribution(distributionLocation string) (int, error) {
+	zipReader, err := zip.OpenReader(distributionLocation)
+	if err != nil {
+		return 0, err
+	}
+	defer zipReader.Close()
+	maxClassVersion := 0
+	for _, file := range zipReader.Reader.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, constant.JAR_EXTENSION) {
+			continue
+		}
+		data, err := readZipEntry(file)
+		if err != nil {
+			return 0, err
+		}
+		classVersion, _, err := util.GetMaxClassVersion(data)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Error occurred while reading the classes of '%s': %v", file.Name, err))
+			continue
+		}
+		if classVersion > maxClassVersion {
+			maxClassVersion = classVersion
+		}
+	}
+	return maxClassVersion, nil
+}
+
 // This function will read the update zip at the the given location.
 func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3, error) {
 	fileMap := make(map[string]bool)
@@ -186,10 +599,19 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 	}
 	defer zipReader.Close()
 
+	// Check for unsafe entries before reading the content of the update
+	if err = util.ValidateZipEntries(zipReader.Reader.File); err != nil {
+		return nil, nil, err
+	}
+
 	updateName := viper.GetString(constant.UPDATE_NAME)
 	logger.Debug("UpdateName:", updateName)
 	// Iterate through each file/dir found in
 	for _, file := range zipReader.Reader.File {
+		if file.Mode()&os.ModeSymlink != 0 {
+			return nil, nil, errors.New(fmt.Sprintf("Symbolic link found: '%s'. Symbolic links are not allowed "+
+				"in updates.", file.Name))
+		}
 		name := getFileName(file.FileInfo().Name())
 		if file.FileInfo().IsDir() {
 			logger.Debug(fmt.Sprintf("filepath: %s", file.Name))
@@ -198,8 +620,8 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 			if name != updateName {
 				logger.Debug("Checking:", name)
 				//Check
-				prefix := filepath.Join(updateName, constant.CARBON_HOME)
-				hasPrefix := strings.HasPrefix(file.Name, prefix)
+				prefix := path.Join(updateName, constant.CARBON_HOME)
+				hasPrefix := strings.HasPrefix(file.Name, prefix+"/")
 				if !hasPrefix {
 					return nil, nil, errors.New("Unknown directory found: '" + file.Name + "'")
 				}
@@ -208,7 +630,7 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 			//todo: check for ignored files .gitignore
 			logger.Debug(fmt.Sprintf("file.Name: %s", file.Name))
 			logger.Debug(fmt.Sprintf("file.FileInfo().Name(): %s", name))
-			fullPath := filepath.Join(updateName, name)
+			fullPath := path.Join(updateName, name)
 			logger.Debug(fmt.Sprintf("fullPath: %s", fullPath))
 			switch name {
 			case constant.UPDATE_DESCRIPTOR_V2_FILE:
@@ -246,7 +668,7 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 					return nil, nil, err
 				}
 				dataString := string(data)
-				if strings.Contains(dataString, "under Apache License 2.0") {
+				if strings.Contains(dataString, constant.SECURITY_UPDATE_LICENSE_TEXT) {
 					isASecPatch = true
 				}
 			case constant.INSTRUCTIONS_FILE:
@@ -263,17 +685,16 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 			default:
 				resourceFiles := getResourceFiles()
 				logger.Debug(fmt.Sprintf("resourceFiles: %v", resourceFiles))
-				prefix := filepath.Join(updateName, constant.CARBON_HOME)
+				prefix := path.Join(updateName, constant.CARBON_HOME)
 				logger.Debug(fmt.Sprintf("Checking prefix %s in %s", prefix, file.Name))
-				hasPrefix := strings.HasPrefix(file.Name, prefix)
+				hasPrefix := strings.HasPrefix(file.Name, prefix+"/")
 				_, foundInResources := resourceFiles[name]
 				logger.Debug(fmt.Sprintf("foundInResources: %v", foundInResources))
 				if !hasPrefix && !foundInResources {
 					return nil, nil, errors.New(fmt.Sprintf("Unknown file found: '%s'.", file.Name))
 				}
-				logger.Debug(fmt.Sprintf("Trimming: %s using %s", file.Name,
-					prefix+constant.PATH_SEPARATOR))
-				relativePath := strings.TrimPrefix(file.Name, prefix+constant.PATH_SEPARATOR)
+				logger.Debug(fmt.Sprintf("Trimming: %s using %s", file.Name, prefix+"/"))
+				relativePath := strings.TrimPrefix(file.Name, prefix+"/")
 				fileMap[relativePath] = false
 			}
 		}
@@ -290,7 +711,7 @@ func readUpdateZip(filename string) (map[string]bool, *util.UpdateDescriptorV3,
 	return fileMap, &updateDescriptorV3, nil
 }
 
-// This function will validate the provided file. If the word 'patch' is found, a warning message is printed.
+// This function will validate the location of the provided file and return its content.
 func validateFile(file *zip.File, fileName, fullPath, updateName string) ([]byte, error) {
 	logger.Debug(fmt.Sprintf("Validating '%s' at '%s' started.", fileName, fullPath))
 	parent := strings.TrimSuffix(file.Name, getFileName(file.FileInfo().Name()))
@@ -313,38 +734,18 @@ func validateFile(file *zip.File, fileName, fullPath, updateName string) ([]byte
 	zippedFile.Close()
 	// Validate checksum of the LICENSE.txt file.
 	if fileName == constant.LICENSE_FILE {
-		err := validateMD5(fileName, parent, constant.LICENSE_MD5_URL, constant.LICENSE_MD5, data)
+		err := validateChecksum(fileName, parent, licenseChecksumSource, data)
 		if err != nil {
 			return nil, err
 		}
 	}
 	// Validate checksum of the NOT_A_CONTRIBUTION.txt file.
 	if fileName == constant.NOT_A_CONTRIBUTION_FILE {
-		err := validateMD5(fileName, parent, constant.NOT_A_CONTRIBUTION_MD5_URL, constant.NOT_A_CONTRIBUTION_MD5, data)
+		err := validateChecksum(fileName, parent, notAContributionChecksumSource, data)
 		if err != nil {
 			return nil, err
 		}
 	}
-	dataString := string(data)
-	dataString = util.ProcessString(dataString, "\n", true)
-
-	//check
-	regex, err := regexp.Compile(constant.PATCH_REGEX)
-	allMatches := regex.FindAllStringSubmatch(dataString, -1)
-	logger.Debug(fmt.Sprintf("All matches: %v", allMatches))
-	isPatchWordFound := false
-	if len(allMatches) > 0 {
-		isPatchWordFound = true
-	}
-	if isPatchWordFound {
-		util.PrintWarning(fmt.Sprintf("'%v' file contains the word 'patch' in following lines. Please "+
-			"review and change it to 'update' if possible.\n", fileName))
-		for i, line := range allMatches {
-			util.PrintInfo(fmt.Sprintf("Matching Line #%d - %v\n", i+1, line[0]))
-		}
-		fmt.Println()
-	}
-
 	logger.Debug(fmt.Sprintf("Validating '%s' finished.", fileName))
 	return data, nil
 }
@@ -384,22 +785,78 @@ func getFileName(filename string) string {
 	return filename
 }
 
-func validateMD5(fileName, parent, md5DownloadUrl, md5hashName string, data []byte) error {
-	logger.Debug(fmt.Sprintf("Checking MD5 of the '%s'", fileName))
-	actualMD5Sum := fmt.Sprintf("%x", md5.Sum(data))
-	expectedMD5Sum, exists := os.LookupEnv(md5hashName)
-	if !exists {
-		expectedMD5SumByte, err := util.GetContentFromUrl(md5DownloadUrl)
-		if err != nil {
-			util.HandleErrorAndExit(err, fmt.Sprintf("Error occurred while getting md5 from: %s.",
-				md5DownloadUrl))
-		}
-		expectedMD5Sum = strings.ToLower(string(expectedMD5SumByte))
+// This function will validate the checksum of the given resource file. The expected SHA-256 checksum is read from the
+// given environment variable or the given URL. The MD5 checksum is only used if MD5 is the configured hash algorithm.
+func validateChecksum(fileName, parent string, checksumSource resourceChecksumSource, data []byte) error {
+	algorithm, expectedChecksum, err := getExpectedChecksum(fileName, checksumSource)
+	if err != nil {
+		return err
+	}
+	logger.Debug(fmt.Sprintf("Checking %s of the '%s'", algorithm, fileName))
+	actualChecksum, err := util.GetChecksumOfData(data, algorithm)
+	if err != nil {
+		return err
 	}
-	if actualMD5Sum != expectedMD5Sum {
-		logger.Debug(fmt.Sprintf("MD5 checksum failed for the file '%s': "+
-			"Expected-'%s', Actual-'%s'", fileName, expectedMD5Sum, actualMD5Sum))
+	if actualChecksum != expectedChecksum {
+		logger.Debug(fmt.Sprintf("%s checksum failed for the file '%s': "+
+			"Expected-'%s', Actual-'%s'", algorithm, fileName, expectedChecksum, actualChecksum))
 		return errors.New(fmt.Sprintf("'%s' in '%s' is invalid.", fileName, parent))
 	}
 	return nil
 }
+
+// This function returns the hash algorithm and the expected checksum of the given resource file. Environment variables
+// take precedence over the URLs and SHA-256 takes precedence over MD5. MD5 checksums are only accepted if MD5 is the
+// configured hash algorithm, so that the validation cannot be downgraded by making the SHA-256 checksum unavailable.
+func getExpectedChecksum(fileName string, checksumSource resourceChecksumSource) (string, string, error) {
+	if checksum, exists := os.LookupEnv(checksumSource.sha256EnvName); exists {
+		return constant.HASH_ALGORITHM_SHA256, strings.ToLower(strings.TrimSpace(checksum)), nil
+	}
+	isMD5Allowed := util.GetHashAlgorithm() == constant.HASH_ALGORITHM_MD5
+	if checksum, exists := os.LookupEnv(checksumSource.md5EnvName); exists {
+		if !isMD5Allowed {
+			return "", "", errors.New(fmt.Sprintf("MD5 checksum of '%s' is given using %s, but MD5 checksums are "+
+				"only accepted if %s is '%s'. Please set %s instead", fileName, checksumSource.md5EnvName,
+				constant.HASH_ALGORITHM, constant.HASH_ALGORITHM_MD5, checksumSource.sha256EnvName))
+		}
+		return constant.HASH_ALGORITHM_MD5, strings.ToLower(strings.TrimSpace(checksum)), nil
+	}
+	// Resource files are taken from the product catalog in the offline mode, so they are validated against it
+	if isOfflineEnabled {
+		filePath, err := util.GetOfflineResourceFilePath(fileName)
+		if err != nil {
+			return "", "", err
+		}
+		checksum, err := util.GetChecksum(filePath, constant.HASH_ALGORITHM_SHA256)
+		if err != nil {
+			return "", "", err
+		}
+		return constant.HASH_ALGORITHM_SHA256, checksum, nil
+	}
+	checksum, err := util.GetContentFromUrl(checksumSource.sha256Url)
+	if err == nil {
+		return constant.HASH_ALGORITHM_SHA256, parseChecksum(checksum), nil
+	}
+	if !isMD5Allowed {
+		return "", "", errors.New(fmt.Sprintf("Error occurred while getting sha256 of '%s' from: %s. %v", fileName,
+			checksumSource.sha256Url, err))
+	}
+	logger.Debug(fmt.Sprintf("Error occurred while getting sha256 of '%s' from: %s. %v", fileName,
+		checksumSource.sha256Url, err))
+	checksum, err = util.GetContentFromUrl(checksumSource.md5Url)
+	if err != nil {
+		return "", "", errors.New(fmt.Sprintf("Error occurred while getting md5 from: %s. %v",
+			checksumSource.md5Url, err))
+	}
+	return constant.HASH_ALGORITHM_MD5, parseChecksum(checksum), nil
+}
+
+// This function will parse the content of a checksum file. Checksum files can contain the file name after the
+// checksum (ie. output of sha256sum command).
+func parseChecksum(content []byte) string {
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
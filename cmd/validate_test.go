@@ -0,0 +1,257 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"github.com/wso2/update-creator-tool/util"
+)
+
+func TestGetExpectedChecksumWithoutSHA256(t *testing.T) {
+	// Only the MD5 checksum is published
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/LICENSE.txt.md5" {
+			http.NotFound(writer, request)
+			return
+		}
+		writer.Write([]byte("0123456789abcdef0123456789abcdef  LICENSE.txt\n"))
+	}))
+	defer server.Close()
+	defer viper.Set(constant.HASH_ALGORITHM, nil)
+	checksumSource := resourceChecksumSource{
+		sha256EnvName: "WUMUC_TEST_LICENSE_SHA256",
+		sha256Url:     server.URL + "/LICENSE.txt.sha256",
+		md5EnvName:    "WUMUC_TEST_LICENSE_MD5",
+		md5Url:        server.URL + "/LICENSE.txt.md5",
+	}
+
+	// MD5 checksums should not be used unless MD5 is the configured hash algorithm
+	viper.Set(constant.HASH_ALGORITHM, constant.HASH_ALGORITHM_SHA256)
+	if algorithm, checksum, err := getExpectedChecksum(constant.LICENSE_FILE, checksumSource); err == nil {
+		t.Errorf("Test failed, expected an error, found: %s %s", algorithm, checksum)
+	}
+	os.Setenv(checksumSource.md5EnvName, "0123456789abcdef0123456789abcdef")
+	defer os.Unsetenv(checksumSource.md5EnvName)
+	if algorithm, checksum, err := getExpectedChecksum(constant.LICENSE_FILE, checksumSource); err == nil {
+		t.Errorf("Test failed, expected an error, found: %s %s", algorithm, checksum)
+	}
+	os.Unsetenv(checksumSource.md5EnvName)
+
+	viper.Set(constant.HASH_ALGORITHM, constant.HASH_ALGORITHM_MD5)
+	algorithm, checksum, err := getExpectedChecksum(constant.LICENSE_FILE, checksumSource)
+	if err != nil || algorithm != constant.HASH_ALGORITHM_MD5 || checksum != "0123456789abcdef0123456789abcdef" {
+		t.Errorf("Test failed, unexpected checksum: %s %s, error: %v", algorithm, checksum, err)
+	}
+
+	// Mismatching checksums should be returned as errors
+	if err = validateChecksum(constant.LICENSE_FILE, "update", checksumSource, []byte("license")); err == nil {
+		t.Error("Test failed, expected an error for an invalid checksum")
+	}
+}
+
+func TestGetExpectedChecksumInOfflineMode(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	writeTestFiles(t, tempDir, map[string]string{constant.LICENSE_FILE: "license"})
+	defer viper.Set(constant.PRODUCT_CATALOG, nil)
+	viper.Set(constant.PRODUCT_CATALOG, tempDir)
+	defer func() { isOfflineEnabled = false }()
+	if err = validateCmd.Flags().Parse([]string{"--offline"}); err != nil || !isOfflineEnabled {
+		t.Fatalf("Test failed, offline mode not enabled using the flag: %v", err)
+	}
+
+	// Checksum should be read from the product catalog without connecting to the server
+	checksumSource := resourceChecksumSource{
+		sha256EnvName: "WUMUC_TEST_LICENSE_SHA256",
+		sha256Url:     "http://127.0.0.1:0/LICENSE.txt.sha256",
+	}
+	expected, err := util.GetChecksumOfData([]byte("license"), constant.HASH_ALGORITHM_SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	algorithm, checksum, err := getExpectedChecksum(constant.LICENSE_FILE, checksumSource)
+	if err != nil || algorithm != constant.HASH_ALGORITHM_SHA256 || checksum != expected {
+		t.Errorf("Test failed, unexpected checksum: %s %s, error: %v", algorithm, checksum, err)
+	}
+	if err = validateChecksum(constant.LICENSE_FILE, "update", checksumSource, []byte("modified")); err == nil {
+		t.Error("Test failed, expected an error for an invalid checksum")
+	}
+}
+
+func TestScanUpdateForSecrets(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	updateFilePath := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-0001.zip")
+	updateFile, err := os.Create(updateFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipWriter := zip.NewWriter(updateFile)
+	files := map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-0001/instructions.txt":                          "password=admin",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/conf/carbon.xml":    "<Password>admin</Password>",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/resources/store.ks": "\xFE\xED\xFE\xEDbinary",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/components/a.jar":   "password=binary",
+	}
+	for name, content := range files {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	if err = zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	updateFile.Close()
+
+	findings, err := scanUpdateForSecrets(updateFilePath)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	rules := make(map[string]string)
+	for _, finding := range findings {
+		rules[finding.FilePath] = finding.Rule
+	}
+	expected := map[string]string{
+		"carbon.home/repository/conf/carbon.xml":    constant.SECRET_RULE_PASSWORD,
+		"carbon.home/repository/resources/store.ks": constant.SECRET_RULE_KEYSTORE,
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Test failed, expected: %v, actual: %v", expected, findings)
+	}
+}
+
+// This function returns the content of a jar with a class of the given major version.
+func createTestJar(t *testing.T, majorVersion int) string {
+	buffer := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buffer)
+	writer, err := zipWriter.Create("org/wso2/A.class")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Write([]byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, byte(majorVersion >> 8), byte(majorVersion)})
+	if err = zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.String()
+}
+
+func TestGetMaxClassVersionOfDistribution(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	distributionPath := filepath.Join(tempDir, "wso2am-2.1.0.zip")
+	createTestZip(t, distributionPath, map[string]string{
+		"wso2am-2.1.0/repository/components/plugins/a.jar": createTestJar(t, 51),
+		"wso2am-2.1.0/repository/components/plugins/b.jar": createTestJar(t, 52),
+	})
+
+	defer viper.Set(constant.WUM_UC_HOME, nil)
+	viper.Set(constant.WUM_UC_HOME, filepath.Join(tempDir, "home"))
+
+	// Highest class version of all the jars in the distribution should be used
+	version, err := getMaxClassVersionOfDistribution(distributionPath)
+	if err != nil || version != 52 {
+		t.Errorf("Test failed, expected: %d, actual: %d, error: %v", 52, version, err)
+	}
+	checksum, err := util.GetChecksum(distributionPath, constant.HASH_ALGORITHM_SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := util.LoadClassVersionCache(filepath.Join(tempDir, "home", constant.WUMUC_CACHE_DIRECTORY,
+		constant.CLASS_VERSION_CACHE_FILE))
+	if cachedVersion, found := cache.Get(checksum); !found || cachedVersion != 52 {
+		t.Errorf("Test failed, expected a cached version of %d, actual: %d", 52, cachedVersion)
+	}
+
+	// Jars can be recompiled for the highest Java version of the distribution
+	updatedJars := map[string][]byte{"repository/components/plugins/a.jar": []byte(createTestJar(t, 52))}
+	if err = checkClassVersions(updatedJars, distributionPath, "4.4.0"); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+	// Added jars should be checked as well
+	updatedJars["repository/components/plugins/c.jar"] = []byte(createTestJar(t, 53))
+	if err = checkClassVersions(updatedJars, distributionPath, "4.4.0"); err == nil {
+		t.Error("Test failed, expected an error for a jar compiled for a newer Java version")
+	}
+}
+
+func TestReadUpdatedJars(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Set(constant.UPDATE_NAME, nil)
+	viper.Set(constant.UPDATE_NAME, "WSO2-CARBON-UPDATE-4.4.0-0001")
+	updateFilePath := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-0001.zip")
+	createTestZip(t, updateFilePath, map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-0001/LICENSE.txt":                                     "license",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/components/plugins/a.jar": "a",
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/conf/carbon.xml":          "<new/>",
+	})
+
+	// Keys should be separated by '/' as they are compared with the names of the zip entries of the distribution
+	updatedJars, err := readUpdatedJars(updateFilePath)
+	expected := map[string][]byte{"repository/components/plugins/a.jar": []byte("a")}
+	if err != nil || !reflect.DeepEqual(updatedJars, expected) {
+		t.Errorf("Test failed, expected: %v, actual: %v, error: %v", expected, updatedJars, err)
+	}
+}
+
+func TestVerifyChecksumManifestWithoutManifest(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Set(constant.UPDATE_NAME, nil)
+	viper.Set(constant.UPDATE_NAME, "WSO2-CARBON-UPDATE-4.4.0-0001")
+	updateFilePath := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-0001.zip")
+	createTestZip(t, updateFilePath, map[string]string{
+		"WSO2-CARBON-UPDATE-4.4.0-0001/carbon.home/repository/conf/carbon.xml": "<new/>",
+	})
+
+	// Manifest is created with every update which has a SHA-256 checksum, so it should not be possible to remove it
+	if err = verifyChecksumManifest(updateFilePath, &util.UpdateDescriptorV3{Sha256sum: "checksum"}); err == nil ||
+		!strings.Contains(err.Error(), constant.CHECKSUMS_FILE) {
+		t.Errorf("Test failed, expected an error for the missing %s, found: %v", constant.CHECKSUMS_FILE, err)
+	}
+	// Legacy updates do not have a manifest
+	if err = verifyChecksumManifest(updateFilePath, &util.UpdateDescriptorV3{Md5sum: "checksum"}); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// Used to serialise the token renewals within the process. Renewals of other wum-uc processes are serialised using
+// the lock file in the wum-uc home.
+var tokenLock sync.Mutex
+
+// This function will store the tokens in the given token response along with the time they were issued.
+func (wumucConfig *WUMUCConfig) SetTokens(tokenResponse *TokenResponse) {
+	wumucConfig.AccessToken = tokenResponse.AccessToken
+	wumucConfig.RefreshToken = tokenResponse.RefreshToken
+	wumucConfig.ExpiresIn = tokenResponse.ExpiresIn
+	wumucConfig.TokenIssuedAt = time.Now().UTC().Unix()
+}
+
+// This function will remove the stored tokens and the client secret.
+func (wumucConfig *WUMUCConfig) ClearTokens() {
+	wumucConfig.setCredentials(&Credentials{})
+}
+
+// This function returns the time at which the access token expires. False is returned if the expiry time is not known,
+// which is the case for the tokens stored by older versions of wum-uc.
+func (wumucConfig *WUMUCConfig) GetAccessTokenExpiryTime() (time.Time, bool) {
+	if wumucConfig.ExpiresIn <= 0 || wumucConfig.TokenIssuedAt <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(wumucConfig.TokenIssuedAt, 0).Add(time.Duration(wumucConfig.ExpiresIn) * time.Second), true
+}
+
+// This function checks whether the access token expires within the given margin. If the expiry time is not known,
+// the token is considered valid until the server rejects it.
+func (wumucConfig *WUMUCConfig) IsAccessTokenExpiring(margin time.Duration) bool {
+	expiryTime, found := wumucConfig.GetAccessTokenExpiryTime()
+	return found && time.Now().Add(margin).After(expiryTime)
+}
+
+// This function returns the URL of the token revocation API.
+func (wumucConfig *WUMUCConfig) GetRevokeURL() string {
+	if len(wumucConfig.RevokeURL) > 0 {
+		return wumucConfig.RevokeURL
+	}
+	return strings.TrimSuffix(wumucConfig.TokenURL, constant.TOKEN_API_CONTEXT) + constant.REVOKE_API_CONTEXT
+}
+
+// This function will obtain an access token if it is not available yet, or renew it if it is about to expire, so that
+// the requests are not rejected by the server.
+func EnsureValidAccessToken() {
+	wumucConfig := GetWUMUCConfigs()
+	canRenew := len(wumucConfig.RefreshToken) > 0 || wumucConfig.hasClientCredentials()
+	if !canRenew || (len(wumucConfig.AccessToken) > 0 &&
+		!wumucConfig.IsAccessTokenExpiring(constant.TOKEN_EXPIRY_MARGIN_IN_SECONDS*time.Second)) {
+		return
+	}
+	logger.Debug("Access token is not available or about to expire. Obtaining a new access token")
+	renewAccessToken(wumucConfig.AccessToken)
+}
+
+// This function will renew the given stale access token and persist the new tokens in the credential store. If another
+// goroutine or wum-uc process has already renewed it, the renewed tokens are used instead of renewing them again.
+// Credentials given using environment variables are not persisted.
+func renewAccessToken(staleAccessToken string) {
+	tokenLock.Lock()
+	defer tokenLock.Unlock()
+	wumucConfig := GetWUMUCConfigs()
+	if wumucConfig.credentialsFromEnvironment {
+		renewEnvironmentAccessToken(wumucConfig, staleAccessToken)
+		return
+	}
+
+	unlock, err := lockConfigFile(wumucConfigFilePath)
+	if err != nil {
+		HandleErrorAndExit(err, "Error occurred while renewing the access token.")
+	}
+	defer unlock()
+
+	latestConfig, _, err := readConfigFile(wumucConfigFilePath, wumucConfig.profile)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Error occurred while reading the tokens in %s: %v", wumucConfigFilePath, err))
+		latestConfig = wumucConfig
+	}
+	if len(latestConfig.AccessToken) > 0 && latestConfig.AccessToken != staleAccessToken &&
+		!latestConfig.IsAccessTokenExpiring(constant.TOKEN_EXPIRY_MARGIN_IN_SECONDS*time.Second) {
+		logger.Debug("Access token has already been renewed")
+		wumucConfig.setCredentials(latestConfig.getCredentials())
+		return
+	}
+	// Refresh token in the credential store is the latest, as the previous one is invalidated once it is used
+	if len(latestConfig.RefreshToken) > 0 {
+		wumucConfig.RefreshToken = latestConfig.RefreshToken
+	}
+
+	tokenResponse, err := obtainAccessToken(wumucConfig)
+	if err != nil {
+		HandleErrorAndExit(err)
+	}
+	wumucConfig.SetTokens(tokenResponse)
+	if err = WriteConfigFile(wumucConfig, wumucConfigFilePath); err != nil {
+		logger.Error(fmt.Sprintf("Error occurred while writing the renewed tokens to %s: %v", wumucConfigFilePath,
+			err))
+	}
+}
+
+// This function will renew the access token given using environment variables. Access token in the token file is read
+// again, as it might have been replaced by the build system.
+func renewEnvironmentAccessToken(wumucConfig *WUMUCConfig, staleAccessToken string) {
+	if len(wumucConfig.AccessToken) > 0 && wumucConfig.AccessToken != staleAccessToken {
+		logger.Debug("Access token has already been renewed")
+		return
+	}
+	if tokenFile := os.Getenv(constant.WUMUC_TOKEN_FILE); len(tokenFile) > 0 {
+		accessToken, err := readTokenFile(tokenFile)
+		if err != nil {
+			HandleErrorAndExit(err)
+		}
+		if accessToken == staleAccessToken {
+			HandleErrorAndExit(errors.New(fmt.Sprintf("access token in the %s '%s' is invalid or expired",
+				constant.WUMUC_TOKEN_FILE, tokenFile)))
+		}
+		wumucConfig.setCredentials(&Credentials{AccessToken: accessToken})
+		return
+	}
+	tokenResponse, err := obtainAccessToken(wumucConfig)
+	if err != nil {
+		HandleErrorAndExit(err)
+	}
+	wumucConfig.SetTokens(tokenResponse)
+}
+
+// This function will get a new access token using the refresh token, or else using the client credentials.
+func obtainAccessToken(wumucConfig *WUMUCConfig) (*TokenResponse, error) {
+	if len(wumucConfig.RefreshToken) > 0 {
+		return RenewAccessToken(wumucConfig)
+	}
+	if wumucConfig.hasClientCredentials() {
+		return GetClientCredentialsToken(wumucConfig)
+	}
+	return nil, errors.New(constant.YOU_HAVENT_INITIALIZED_WUMUC_YET_MSG + " " + constant.
+		RUN_WUMUC_INIT_TO_CONTINUE_MSG)
+}
+
+// This function will acquire the lock file next to the given config file and return a function which releases it.
+// The lock file is refreshed while it is held, so that a long running token request does not make it stale. Lock files
+// which are not refreshed within the lock timeout are left behind by the processes which did not release them, and they
+// are removed.
+func lockConfigFile(wumucConfigFilePath string) (func(), error) {
+	lockFilePath := filepath.Join(filepath.Dir(wumucConfigFilePath), constant.WUMUC_CONFIG_LOCK_FILE)
+	timeout := constant.WUMUC_CONFIG_LOCK_TIMEOUT_IN_SECONDS * time.Second
+	deadline := time.Now().Add(constant.WUMUC_CONFIG_LOCK_WAIT_TIMEOUT_IN_SECONDS * time.Second)
+	// Identifies the lock of this call, so that a lock acquired by another process is never released by this one
+	owner := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+	for {
+		lockFile, err := os.OpenFile(lockFilePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = lockFile.WriteString(owner)
+			lockFile.Close()
+			if err != nil {
+				os.Remove(lockFilePath)
+				return nil, err
+			}
+			return holdLockFile(lockFilePath, owner), nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockFilePath); err == nil && time.Since(info.ModTime()) > timeout {
+			logger.Debug(fmt.Sprintf("Removing the stale lock file %s", lockFilePath))
+			os.Remove(lockFilePath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New(fmt.Sprintf("timed out while waiting for the lock file '%s'. Remove it if no "+
+				"other wum-uc process is running", lockFilePath))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// This function will refresh the given lock file until the returned function is called. The returned function removes
+// the lock file only if it is still owned by the given owner.
+func holdLockFile(lockFilePath, owner string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(constant.WUMUC_CONFIG_LOCK_REFRESH_INTERVAL_IN_SECONDS * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				os.Chtimes(lockFilePath, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		data, err := ioutil.ReadFile(lockFilePath)
+		if err != nil || string(data) != owner {
+			logger.Debug(fmt.Sprintf("Lock file %s is not owned by this process, so it is not removed", lockFilePath))
+			return
+		}
+		os.Remove(lockFilePath)
+	}
+}
+
+// This function will revoke the given token using the token revocation API.
+func RevokeToken(wumucConfig *WUMUCConfig, token, tokenTypeHint string) error {
+	payload := url.Values{}
+	payload.Add("token", token)
+	payload.Add("token_type_hint", tokenTypeHint)
+	revokeURL := wumucConfig.GetRevokeURL()
+	request, err := http.NewRequest(http.MethodPost, revokeURL, bytes.NewBufferString(payload.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Add(constant.HEADER_AUTHORIZATION, wumucConfig.getClientAuthorization())
+	request.Header.Add(constant.HEADER_CONTENT_TYPE, constant.HEADER_VALUE_X_WWW_FORM_URLENCODED)
+
+	response, err := SendRequest(request)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return newHTTPError(revokeURL, response)
+	}
+	response.Body.Close()
+	return nil
+}
+
+// This function will revoke the stored tokens and remove them from the credential store along with the client
+// credentials. Tokens are removed even if they cannot be revoked, so that they are not used anymore.
+func Logout() {
+	tokenLock.Lock()
+	defer tokenLock.Unlock()
+	wumucConfig := GetWUMUCConfigs()
+	if *wumucConfig.getCredentials() == (Credentials{}) {
+		PrintInfo("You are not logged in.")
+		return
+	}
+	if wumucConfig.credentialsFromEnvironment {
+		HandleErrorAndExit(errors.New(fmt.Sprintf("credentials are given using the environment variables. Unset %s, "+
+			"%s and %s to log out", constant.WUMUC_CLIENT_ID, constant.WUMUC_CLIENT_SECRET, constant.WUMUC_TOKEN_FILE)))
+	}
+
+	unlock, err := lockConfigFile(wumucConfigFilePath)
+	if err != nil {
+		HandleErrorAndExit(err, "Error occurred while logging out.")
+	}
+	defer unlock()
+	tokens := []struct{ token, hint string }{
+		{wumucConfig.RefreshToken, "refresh_token"},
+		{wumucConfig.AccessToken, "access_token"},
+	}
+	for _, token := range tokens {
+		if len(token.token) == 0 {
+			continue
+		}
+		if err := RevokeToken(wumucConfig, token.token, token.hint); err != nil {
+			PrintWarning(fmt.Sprintf("Unable to revoke the %s: %v", strings.Replace(token.hint, "_", " ", -1), err))
+		}
+	}
+	wumucConfig.ClearTokens()
+	wumucConfig.ClientID = ""
+	if err = WriteConfigFile(wumucConfig, wumucConfigFilePath); err != nil {
+		HandleErrorAndExit(err, fmt.Sprintf("Error occurred while removing the tokens from %s.",
+			wumucConfigFilePath))
+	}
+}
+
+// This function checks whether the credentials are given using the environment variables.
+func (wumucConfig *WUMUCConfig) AreCredentialsFromEnvironment() bool {
+	return wumucConfig.credentialsFromEnvironment
+}
+
+// This function checks whether the OAuth2 client credentials are available.
+func (wumucConfig *WUMUCConfig) hasClientCredentials() bool {
+	return len(wumucConfig.ClientID) > 0 && len(wumucConfig.ClientSecret) > 0
+}
+
+// This function returns the value of the basic authorization header sent to the token API. Client credentials are
+// used if they are available, or else the application key of wum-uc.
+func (wumucConfig *WUMUCConfig) getClientAuthorization() string {
+	if wumucConfig.hasClientCredentials() {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(wumucConfig.ClientID+":"+wumucConfig.ClientSecret))
+	}
+	return "Basic " + wumucConfig.AppKey
+}
+
+// This function will use the credentials given using the environment variables, if any. Access token in the token
+// file takes precedence over the client credentials.
+func (wumucConfig *WUMUCConfig) applyEnvironmentCredentials() error {
+	clientID := os.Getenv(constant.WUMUC_CLIENT_ID)
+	clientSecret := os.Getenv(constant.WUMUC_CLIENT_SECRET)
+	if tokenFile := os.Getenv(constant.WUMUC_TOKEN_FILE); len(tokenFile) > 0 {
+		accessToken, err := readTokenFile(tokenFile)
+		if err != nil {
+			return err
+		}
+		logger.Debug(fmt.Sprintf("Using the access token in %s", tokenFile))
+		wumucConfig.setCredentials(&Credentials{AccessToken: accessToken})
+		wumucConfig.ClientID = ""
+		wumucConfig.credentialsFromEnvironment = true
+		return nil
+	}
+	if len(clientID) == 0 && len(clientSecret) == 0 {
+		return nil
+	}
+	if len(clientID) == 0 || len(clientSecret) == 0 {
+		return errors.New(fmt.Sprintf("both %s and %s environment variables should be set", constant.WUMUC_CLIENT_ID,
+			constant.WUMUC_CLIENT_SECRET))
+	}
+	logger.Debug(fmt.Sprintf("Using the client credentials of %s", clientID))
+	wumucConfig.setCredentials(&Credentials{ClientSecret: clientSecret})
+	wumucConfig.ClientID = clientID
+	wumucConfig.credentialsFromEnvironment = true
+	return nil
+}
+
+// This function will read the access token in the given token file.
+func readTokenFile(tokenFile string) (string, error) {
+	data, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("unable to read the %s '%s'. %v", constant.WUMUC_TOKEN_FILE, tokenFile,
+			err))
+	}
+	accessToken := strings.TrimSpace(string(data))
+	if len(accessToken) == 0 {
+		return "", errors.New(fmt.Sprintf("%s '%s' is empty", constant.WUMUC_TOKEN_FILE, tokenFile))
+	}
+	return accessToken, nil
+}
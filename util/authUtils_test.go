@@ -0,0 +1,276 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function will start the development server, counting the requests to the token and revocation APIs, and
+// store a config.yaml with the given tokens in a temporary wum-uc home.
+func startTokenServer(t *testing.T, issuedAt int64) (*int32, *int32, func()) {
+	_, devServer, devServerCleanup := startDevServer(t)
+	var tokenRequests, revokeRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + constant.TOKEN_API_CONTEXT:
+			atomic.AddInt32(&tokenRequests, 1)
+		case "/" + constant.REVOKE_API_CONTEXT:
+			atomic.AddInt32(&revokeRequests, 1)
+		}
+		request, _ := http.NewRequest(r.Method, devServer.URL+r.URL.Path, r.Body)
+		request.Header = r.Header
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer response.Body.Close()
+		w.WriteHeader(response.StatusCode)
+		data, _ := ioutil.ReadAll(response.Body)
+		w.Write(data)
+	}))
+	wumucHome, err := ioutil.TempDir("", "wum-uc-home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousConfig, previousConfigFilePath := wumucConfig, wumucConfigFilePath
+	wumucConfig = WUMUCConfig{
+		ServerURL:     server.URL,
+		TokenURL:      server.URL + "/" + constant.TOKEN_API_CONTEXT,
+		AccessToken:   "old-access-token",
+		RefreshToken:  "old-refresh-token",
+		ExpiresIn:     3600,
+		TokenIssuedAt: issuedAt,
+	}
+	wumucConfigFilePath = filepath.Join(wumucHome, constant.WUMUC_CONFIG_FILE)
+	if err = WriteConfigFile(&wumucConfig, wumucConfigFilePath); err != nil {
+		t.Fatal(err)
+	}
+	return &tokenRequests, &revokeRequests, func() {
+		wumucConfig, wumucConfigFilePath = previousConfig, previousConfigFilePath
+		server.Close()
+		devServerCleanup()
+		os.RemoveAll(wumucHome)
+	}
+}
+
+func TestAccessTokenExpiry(t *testing.T) {
+	config := WUMUCConfig{}
+	if _, found := config.GetAccessTokenExpiryTime(); found || config.IsAccessTokenExpiring(time.Minute) {
+		t.Error("Test failed, expiry time of the tokens without ExpiresIn should be unknown")
+	}
+	config.SetTokens(&TokenResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 3600})
+	if config.IsAccessTokenExpiring(time.Minute) || !config.IsAccessTokenExpiring(2*time.Hour) {
+		t.Errorf("Test failed, unexpected expiry time for the token issued at %d", config.TokenIssuedAt)
+	}
+	config.ClearTokens()
+	if len(config.AccessToken) != 0 || len(config.RefreshToken) != 0 || config.ExpiresIn != 0 {
+		t.Errorf("Test failed, tokens not cleared: %v", config)
+	}
+
+	config.TokenURL = "https://localhost/oauth2/token"
+	if revokeURL := config.GetRevokeURL(); revokeURL != "https://localhost/oauth2/revoke" {
+		t.Errorf("Test failed, unexpected revoke URL: %s", revokeURL)
+	}
+	config.RevokeURL = "https://localhost/revoke"
+	if revokeURL := config.GetRevokeURL(); revokeURL != config.RevokeURL {
+		t.Errorf("Test failed, unexpected revoke URL: %s", revokeURL)
+	}
+}
+
+func TestEnsureValidAccessToken(t *testing.T) {
+	tokenRequests, _, cleanup := startTokenServer(t, time.Now().Add(-time.Hour).Unix())
+	defer cleanup()
+
+	// Expiring token is renewed once, even if it is used concurrently
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			EnsureValidAccessToken()
+		}()
+	}
+	waitGroup.Wait()
+	if *tokenRequests != 1 || wumucConfig.AccessToken != constant.DEV_SERVER_ACCESS_TOKEN {
+		t.Errorf("Test failed, token requests: %d, access token: %s", *tokenRequests, wumucConfig.AccessToken)
+	}
+	storedConfig, _, err := readConfigFile(wumucConfigFilePath, "")
+	if err != nil || storedConfig.RefreshToken != constant.DEV_SERVER_REFRESH_TOKEN ||
+		storedConfig.IsAccessTokenExpiring(time.Minute) {
+		t.Errorf("Test failed, renewed tokens not stored: %v, error: %v", storedConfig, err)
+	}
+
+	// Tokens renewed by another process are used instead of renewing them again
+	storedConfig.AccessToken = "renewed-access-token"
+	if err = WriteConfigFile(storedConfig, wumucConfigFilePath); err != nil {
+		t.Fatal(err)
+	}
+	renewAccessToken(constant.DEV_SERVER_ACCESS_TOKEN)
+	if *tokenRequests != 1 || wumucConfig.AccessToken != "renewed-access-token" {
+		t.Errorf("Test failed, token requests: %d, access token: %s", *tokenRequests, wumucConfig.AccessToken)
+	}
+}
+
+func TestEnsureValidAccessTokenNotExpiring(t *testing.T) {
+	tokenRequests, _, cleanup := startTokenServer(t, time.Now().Unix())
+	defer cleanup()
+	EnsureValidAccessToken()
+	if *tokenRequests != 0 || wumucConfig.AccessToken != "old-access-token" {
+		t.Errorf("Test failed, token requests: %d, access token: %s", *tokenRequests, wumucConfig.AccessToken)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	_, revokeRequests, cleanup := startTokenServer(t, time.Now().Unix())
+	defer cleanup()
+	Logout()
+	if *revokeRequests != 2 {
+		t.Errorf("Test failed, expected both tokens to be revoked, found %d requests", *revokeRequests)
+	}
+	storedConfig, _, err := readConfigFile(wumucConfigFilePath, "")
+	if err != nil || len(storedConfig.AccessToken) != 0 || len(storedConfig.RefreshToken) != 0 ||
+		storedConfig.TokenIssuedAt != 0 {
+		t.Errorf("Test failed, tokens not removed: %v, error: %v", storedConfig, err)
+	}
+	lockFilePath := filepath.Join(filepath.Dir(wumucConfigFilePath), constant.WUMUC_CONFIG_LOCK_FILE)
+	if _, err = os.Stat(lockFilePath); err == nil {
+		t.Error("Test failed, lock file not released")
+	}
+}
+
+func TestLockConfigFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	configFilePath := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	lockFilePath := filepath.Join(tempDir, constant.WUMUC_CONFIG_LOCK_FILE)
+
+	// Lock files which are not refreshed are removed
+	if err = ioutil.WriteFile(lockFilePath, []byte("1-1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	staleTime := time.Now().Add(-2 * constant.WUMUC_CONFIG_LOCK_TIMEOUT_IN_SECONDS * time.Second)
+	if err = os.Chtimes(lockFilePath, staleTime, staleTime); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockConfigFile(configFilePath)
+	if err != nil {
+		t.Fatalf("Test failed, stale lock file not removed: %v", err)
+	}
+	unlock()
+	if _, err = os.Stat(lockFilePath); err == nil {
+		t.Error("Test failed, lock file not released")
+	}
+
+	// Lock acquired by another process should not be released
+	unlock, err = lockConfigFile(configFilePath)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if err = ioutil.WriteFile(lockFilePath, []byte("1-2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if _, err = os.Stat(lockFilePath); err != nil {
+		t.Errorf("Test failed, lock file of another process released: %v", err)
+	}
+}
+
+func TestEnvironmentClientCredentials(t *testing.T) {
+	tokenRequests, _, cleanup := startTokenServer(t, 0)
+	defer cleanup()
+	defer setEnv(constant.WUMUC_TOKEN_FILE, "")()
+	defer setEnv(constant.WUMUC_CLIENT_ID, "robot")()
+	defer setEnv(constant.WUMUC_CLIENT_SECRET, "secret")()
+	wumucConfig.ClearTokens()
+
+	if err := wumucConfig.applyEnvironmentCredentials(); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if !wumucConfig.AreCredentialsFromEnvironment() || wumucConfig.getClientAuthorization() != "Basic cm9ib3Q6c2VjcmV0" {
+		t.Errorf("Test failed, client credentials not applied: %v", wumucConfig)
+	}
+	// Access token is obtained with the first request and not persisted
+	EnsureValidAccessToken()
+	if *tokenRequests != 1 || wumucConfig.AccessToken != constant.DEV_SERVER_ACCESS_TOKEN ||
+		wumucConfig.ClientSecret != "secret" {
+		t.Errorf("Test failed, token requests: %d, config: %v", *tokenRequests, wumucConfig)
+	}
+	EnsureValidAccessToken()
+	if *tokenRequests != 1 {
+		t.Errorf("Test failed, valid access token renewed, token requests: %d", *tokenRequests)
+	}
+	storedConfig, _, err := readConfigFile(wumucConfigFilePath, "")
+	if err != nil || storedConfig.AccessToken == constant.DEV_SERVER_ACCESS_TOKEN || len(storedConfig.ClientID) != 0 {
+		t.Errorf("Test failed, environment credentials persisted: %v, error: %v", storedConfig, err)
+	}
+
+	wumucConfig.ClientSecret = constant.DEV_SERVER_INVALID_PASSWORD
+	if _, err = GetClientCredentialsToken(&wumucConfig); err == nil {
+		t.Error("Test failed, invalid client credentials accepted")
+	}
+	defer setEnv(constant.WUMUC_CLIENT_SECRET, "")()
+	if err = wumucConfig.applyEnvironmentCredentials(); err == nil {
+		t.Error("Test failed, client id accepted without the client secret")
+	}
+}
+
+func TestEnvironmentTokenFile(t *testing.T) {
+	_, _, cleanup := startTokenServer(t, 0)
+	defer cleanup()
+	tokenFile := filepath.Join(filepath.Dir(wumucConfigFilePath), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("first-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer setEnv(constant.WUMUC_TOKEN_FILE, tokenFile)()
+	defer setEnv(constant.WUMUC_CLIENT_ID, "")()
+	defer setEnv(constant.WUMUC_CLIENT_SECRET, "")()
+
+	if err := wumucConfig.applyEnvironmentCredentials(); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if wumucConfig.AccessToken != "first-token" || len(wumucConfig.RefreshToken) != 0 {
+		t.Errorf("Test failed, access token not read from the token file: %v", wumucConfig)
+	}
+	// Rejected access token is replaced by the one in the token file
+	if err := ioutil.WriteFile(tokenFile, []byte("second-token"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	renewAccessToken("first-token")
+	if wumucConfig.AccessToken != "second-token" {
+		t.Errorf("Test failed, access token not read again from the token file: %s", wumucConfig.AccessToken)
+	}
+
+	if err := ioutil.WriteFile(tokenFile, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := wumucConfig.applyEnvironmentCredentials(); err == nil {
+		t.Error("Test failed, empty token file accepted")
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// struct which is used to store the file index of a product in the product catalog. Paths are relative to the
+// PRODUCT_HOME and directories are included.
+type CatalogProduct struct {
+	ProductName string
+	BaseVersion string
+	Tag         string
+	paths       map[string]bool
+}
+
+var catalogProductNameRegex = regexp.MustCompile(constant.CATALOG_PRODUCT_NAME_REGEX)
+
+// This function returns the location of the product catalog. If it is not configured, the catalog directory in
+// $WUMUC_HOME is used.
+func GetProductCatalogPath() string {
+	catalogPath := viper.GetString(constant.PRODUCT_CATALOG)
+	if len(catalogPath) == 0 {
+		catalogPath = filepath.Join(viper.GetString(constant.WUM_UC_HOME), constant.PRODUCT_CATALOG_DIRECTORY)
+	}
+	return catalogPath
+}
+
+// This function will compute the products which are affected by the file changes of the given update using the
+// product catalog instead of the WUM servers. The response has the same format as the response of the WUM servers.
+func GetPartialUpdatedFilesOffline(updateDescriptorV2 *UpdateDescriptorV2) *PartialUpdatedFileResponse {
+	catalogPath := GetProductCatalogPath()
+	products, err := LoadCatalogProducts(filepath.Join(catalogPath, updateDescriptorV2.PlatformName))
+	if err != nil {
+		HandleErrorAndExit(err, fmt.Sprintf("Error occurred while reading the product catalog '%s'.", catalogPath))
+	}
+	if len(products) == 0 {
+		HandleErrorAndExit(errors.New(fmt.Sprintf("no products found for the platform '%s' in the product "+
+			"catalog '%s'", updateDescriptorV2.PlatformName, catalogPath)))
+	}
+	return ComputePartialUpdatedFiles(createPartialUpdateFileRequest(updateDescriptorV2), products)
+}
+
+// This function will read the products in the given catalog directory of a platform. Each product should either be a
+// distribution zip (ie. wso2am-2.1.0.zip) or a file index (ie. wso2am-2.1.0.index) which contains a path relative to
+// the PRODUCT_HOME in each line. Indexes of the distributions are cached in the same directory.
+func LoadCatalogProducts(platformCatalogPath string) ([]*CatalogProduct, error) {
+	files, err := ioutil.ReadDir(platformCatalogPath)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make(map[string]os.FileInfo)
+	distributions := make(map[string]os.FileInfo)
+	for _, file := range files {
+		extension := filepath.Ext(file.Name())
+		name := strings.TrimSuffix(file.Name(), extension)
+		switch extension {
+		case constant.CATALOG_INDEX_EXTENSION:
+			indexes[name] = file
+		case ".zip":
+			distributions[name] = file
+		}
+	}
+	names := make([]string, 0)
+	for name := range indexes {
+		if _, found := distributions[name]; !found {
+			names = append(names, name)
+		}
+	}
+	for name, distribution := range distributions {
+		names = append(names, name)
+		index, found := indexes[name]
+		if found && !index.ModTime().Before(distribution.ModTime()) {
+			continue
+		}
+		logger.Debug(fmt.Sprintf("Creating the file index of %s", distribution.Name()))
+		if err = createCatalogIndex(filepath.Join(platformCatalogPath, distribution.Name()),
+			filepath.Join(platformCatalogPath, name+constant.CATALOG_INDEX_EXTENSION)); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(names)
+	products := make([]*CatalogProduct, 0)
+	for _, name := range names {
+		indexPath := filepath.Join(platformCatalogPath, name+constant.CATALOG_INDEX_EXTENSION)
+		product, err := readCatalogIndex(name, indexPath)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// This function will create the file index of the given distribution zip. The root directory of the distribution is
+// removed from the paths.
+func createCatalogIndex(distributionPath, indexPath string) error {
+	zipReader, err := zip.OpenReader(distributionPath)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+	paths := make([]string, 0)
+	for _, file := range zipReader.File {
+		name := strings.TrimSuffix(file.Name, "/")
+		separatorIndex := strings.Index(name, "/")
+		if separatorIndex == -1 {
+			continue
+		}
+		paths = append(paths, name[separatorIndex+1:])
+	}
+	sort.Strings(paths)
+	return ioutil.WriteFile(indexPath, []byte(strings.Join(paths, "\n")+"\n"), 0644)
+}
+
+// This function will read the file index of the given product.
+func readCatalogIndex(name, indexPath string) (*CatalogProduct, error) {
+	match := catalogProductNameRegex.FindStringSubmatch(name)
+	if match == nil {
+		return nil, errors.New(fmt.Sprintf("invalid product '%s' found in the product catalog. Name should "+
+			"match '%s'", name, constant.CATALOG_PRODUCT_NAME_REGEX))
+	}
+	data, err := ioutil.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	product := CatalogProduct{
+		ProductName: match[1],
+		BaseVersion: match[2],
+		Tag:         match[3],
+		paths:       make(map[string]bool),
+	}
+	for _, line := range SplitLines(string(data)) {
+		line = strings.Trim(strings.TrimSpace(line), "/")
+		if len(line) == 0 {
+			continue
+		}
+		// Parent directories are added as well since some indexes might only contain the files
+		for entry := line; entry != "." && entry != "/"; entry = path.Dir(entry) {
+			product.paths[entry] = true
+		}
+		// Root of the product, which is the parent directory of the files added to the PRODUCT_HOME
+		product.paths["."] = true
+	}
+	return &product, nil
+}
+
+// This function returns the location of the given resource file (ie. LICENSE.txt) in the product catalog. Resource
+// files are taken from the product catalog in the offline mode instead of downloading them.
+func GetOfflineResourceFilePath(fileName string) (string, error) {
+	filePath := filepath.Join(GetProductCatalogPath(), fileName)
+	exists, err := IsFileExists(filePath)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", errors.New(fmt.Sprintf("'%s' not found at '%s'. In the offline mode, %s is taken from the "+
+			"product catalog. Copy it to the product catalog or configure the location of the product catalog "+
+			"using %s", fileName, filePath, fileName, constant.PRODUCT_CATALOG))
+	}
+	return filePath, nil
+}
+
+// This function checks whether the given path exists in the product.
+func (product *CatalogProduct) Contains(filePath string) bool {
+	return product.paths[strings.Trim(filePath, "/")]
+}
+
+// This function will compute the products which contain the files of the given request. A product is compatible if it
+// contains all the modified and removed files and the parent directories of all the added files, and partially
+// applicable if it contains some of them. Products which do not contain any of them are not affected by the update.
+func ComputePartialUpdatedFiles(request *PartialUpdateFileRequest,
+	products []*CatalogProduct) *PartialUpdatedFileResponse {
+	response := PartialUpdatedFileResponse{
+		UpdateNumber:                request.UpdateNumber,
+		PlatformVersion:             request.PlatformVersion,
+		PlatformName:                request.PlatformName,
+		PartiallyApplicableProducts: make([]PartialUpdatedProducts, 0),
+		CompatibleProducts:          make([]PartialUpdatedProducts, 0),
+		NotifyProducts:              make([]PartialUpdatedProducts, 0),
+	}
+	totalFiles := len(request.AddedFiles) + len(request.ModifiedFiles) + len(request.RemovedFiles)
+	for _, product := range products {
+		productChanges := PartialUpdatedProducts{
+			ProductName:   product.ProductName,
+			BaseVersion:   product.BaseVersion,
+			Tag:           product.Tag,
+			AddedFiles:    make([]string, 0),
+			ModifiedFiles: make([]string, 0),
+			RemovedFiles:  make([]string, 0),
+		}
+		for _, filePath := range request.AddedFiles {
+			parentDirectory := path.Dir(strings.Trim(filePath, "/"))
+			if product.Contains(parentDirectory) {
+				productChanges.AddedFiles = append(productChanges.AddedFiles, filePath)
+			}
+		}
+		for _, filePath := range request.ModifiedFiles {
+			if product.Contains(filePath) {
+				productChanges.ModifiedFiles = append(productChanges.ModifiedFiles, filePath)
+			}
+		}
+		for _, filePath := range request.RemovedFiles {
+			if product.Contains(filePath) {
+				productChanges.RemovedFiles = append(productChanges.RemovedFiles, filePath)
+			}
+		}
+		matchingFiles := len(productChanges.AddedFiles) + len(productChanges.ModifiedFiles) +
+			len(productChanges.RemovedFiles)
+		if len(productChanges.ModifiedFiles)+len(productChanges.RemovedFiles) == 0 &&
+			len(request.ModifiedFiles)+len(request.RemovedFiles) > 0 {
+			// Product does not contain any of the files changed by the update
+			continue
+		}
+		if matchingFiles == 0 {
+			continue
+		}
+		if matchingFiles == totalFiles {
+			response.CompatibleProducts = append(response.CompatibleProducts, productChanges)
+		} else {
+			response.PartiallyApplicableProducts = append(response.PartiallyApplicableProducts, productChanges)
+		}
+	}
+	// Updates which are not partially applicable to any product can be installed by the older clients as well
+	response.BackwardCompatible = len(response.PartiallyApplicableProducts) == 0
+	return &response
+}
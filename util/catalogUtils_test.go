@@ -0,0 +1,157 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function will create a product catalog of a platform with a distribution zip and a file index.
+func createProductCatalog(t *testing.T) string {
+	catalogPath, err := ioutil.TempDir("", "wum-uc-catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipFile, err := os.Create(filepath.Join(catalogPath, "wso2am-2.1.0.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipWriter := zip.NewWriter(zipFile)
+	for _, name := range []string{"wso2am-2.1.0/", "wso2am-2.1.0/bin/wso2server.sh",
+		"wso2am-2.1.0/repository/components/plugins/org.wso2.carbon.core_4.4.9.jar",
+		"wso2am-2.1.0/repository/deployment/server/webapps/oauth2.war"} {
+		if _, err = zipWriter.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zipFile.Close()
+	index := "bin/wso2server.sh\nrepository/components/plugins/org.wso2.carbon.core_4.4.9.jar\n"
+	err = ioutil.WriteFile(filepath.Join(catalogPath, "wso2is-5.3.0.1529382635299.index"), []byte(index), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return catalogPath
+}
+
+func TestLoadCatalogProducts(t *testing.T) {
+	catalogPath := createProductCatalog(t)
+	defer os.RemoveAll(catalogPath)
+
+	products, err := LoadCatalogProducts(catalogPath)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if len(products) != 2 {
+		t.Fatalf("Test failed, expected 2 products, found %d", len(products))
+	}
+	if products[0].ProductName != "wso2am" || products[0].BaseVersion != "2.1.0" || products[0].Tag != "" {
+		t.Errorf("Test failed, unexpected product: %v", products[0])
+	}
+	if products[1].ProductName != "wso2is" || products[1].BaseVersion != "5.3.0" ||
+		products[1].Tag != "1529382635299" {
+		t.Errorf("Test failed, unexpected product: %v", products[1])
+	}
+	if !products[0].Contains("repository/deployment/server/webapps/oauth2.war") ||
+		!products[1].Contains("repository/components/plugins") {
+		t.Error("Test failed, paths not found in the products")
+	}
+	if _, err = os.Stat(filepath.Join(catalogPath, "wso2am-2.1.0.index")); err != nil {
+		t.Errorf("Test failed, index of the distribution not cached: %v", err)
+	}
+}
+
+func TestGetOfflineResourceFilePath(t *testing.T) {
+	catalogPath := createProductCatalog(t)
+	defer os.RemoveAll(catalogPath)
+	defer viper.Set(constant.PRODUCT_CATALOG, nil)
+	viper.Set(constant.PRODUCT_CATALOG, catalogPath)
+
+	if _, err := GetOfflineResourceFilePath(constant.LICENSE_FILE); err == nil ||
+		!strings.Contains(err.Error(), constant.PRODUCT_CATALOG) {
+		t.Errorf("Test failed, expected an error for a missing resource file, found: %v", err)
+	}
+	licensePath := filepath.Join(catalogPath, constant.LICENSE_FILE)
+	if err := ioutil.WriteFile(licensePath, []byte("license"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if filePath, err := GetOfflineResourceFilePath(constant.LICENSE_FILE); err != nil || filePath != licensePath {
+		t.Errorf("Test failed, unexpected path: %s, error: %v", filePath, err)
+	}
+}
+
+func TestComputePartialUpdatedFiles(t *testing.T) {
+	catalogPath := createProductCatalog(t)
+	defer os.RemoveAll(catalogPath)
+	products, err := LoadCatalogProducts(catalogPath)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+
+	request := PartialUpdateFileRequest{
+		UpdateNumber:    "0001",
+		PlatformName:    "wilkes",
+		PlatformVersion: "4.4.0",
+		AddedFiles:      []string{"repository/components/plugins/org.wso2.carbon.extra_4.4.9.jar"},
+		ModifiedFiles: []string{"repository/components/plugins/org.wso2.carbon.core_4.4.9.jar",
+			"repository/deployment/server/webapps/oauth2.war"},
+	}
+	response := ComputePartialUpdatedFiles(&request, products)
+	if response.UpdateNumber != "0001" || response.PlatformName != "wilkes" || response.BackwardCompatible {
+		t.Errorf("Test failed, unexpected response: %v", response)
+	}
+	if len(response.CompatibleProducts) != 1 || response.CompatibleProducts[0].ProductName != "wso2am" ||
+		!reflect.DeepEqual(response.CompatibleProducts[0].ModifiedFiles, request.ModifiedFiles) {
+		t.Errorf("Test failed, unexpected compatible products: %v", response.CompatibleProducts)
+	}
+	if len(response.PartiallyApplicableProducts) != 1 {
+		t.Fatalf("Test failed, unexpected partially applicable products: %v",
+			response.PartiallyApplicableProducts)
+	}
+	partial := response.PartiallyApplicableProducts[0]
+	if partial.ProductName != "wso2is" || len(partial.AddedFiles) != 1 || len(partial.ModifiedFiles) != 1 ||
+		partial.ModifiedFiles[0] != request.ModifiedFiles[0] {
+		t.Errorf("Test failed, unexpected partially applicable product: %v", partial)
+	}
+
+	// Files added to the root are only attributed to the products which contain the root
+	request.AddedFiles = []string{"repository/components/plugins/org.wso2.carbon.extra_4.4.9.jar", "NOTICE.txt"}
+	request.ModifiedFiles = nil
+	emptyProduct := CatalogProduct{ProductName: "wso2ei", BaseVersion: "6.1.0", paths: make(map[string]bool)}
+	response = ComputePartialUpdatedFiles(&request, append(products, &emptyProduct))
+	if len(response.CompatibleProducts) != 2 || len(response.PartiallyApplicableProducts) != 0 {
+		t.Errorf("Test failed, unexpected response: %v", response)
+	}
+
+	// Products which do not contain any of the changed files are not affected
+	request.AddedFiles = nil
+	request.ModifiedFiles = []string{"repository/deployment/server/webapps/oauth2.war"}
+	response = ComputePartialUpdatedFiles(&request, products)
+	if len(response.CompatibleProducts) != 1 || len(response.PartiallyApplicableProducts) != 0 ||
+		!response.BackwardCompatible {
+		t.Errorf("Test failed, unexpected response: %v", response)
+	}
+}
@@ -0,0 +1,441 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+// Describes a key which can be configured in the config file.
+type ConfigKey struct {
+	Name        string
+	Type        string
+	Description string
+	// Values allowed for the key. Any value is allowed if this is empty
+	AllowedValues []string
+	// Regex which should be matched by the value, if it is not empty
+	Pattern string
+	// Set if the value should be masked when the configuration is listed
+	IsSecret bool
+	// Set if the value can be overridden in the project configuration (.wum-uc.yaml) of an update directory. Keys
+	// which can weaken the checks of the validate command are not allowed
+	IsProjectKey bool
+	// Set if the value is kept in the WUMUCConfig. Otherwise the value is read using viper
+	isWUMUCConfigField bool
+	// Validates the values of structured keys, which cannot be validated using the type
+	validate func() error
+}
+
+// Keys which can be configured in the config file. Keys of the WUMUCConfig are in lower case as they are read using
+// yaml, while keys read using viper are case insensitive. Credentials, usernames and profiles are not included as
+// they are managed using the init, auth and config profiles commands.
+var configSchema = []ConfigKey{
+	{Name: "serverurl", Type: constant.CONFIG_TYPE_URL, Description: "WUM server URL", isWUMUCConfigField: true},
+	{Name: "tokenurl", Type: constant.CONFIG_TYPE_URL, Description: "Token API of the WUM server",
+		isWUMUCConfigField: true},
+	{Name: "versionurl", Type: constant.CONFIG_TYPE_URL, Description: "API used to check the latest wum-uc version",
+		isWUMUCConfigField: true},
+	{Name: "revokeurl", Type: constant.CONFIG_TYPE_URL, Description: "Token revocation API. Derived from the " +
+		"tokenurl if it is not set", isWUMUCConfigField: true},
+	{Name: "appkey", Type: constant.CONFIG_TYPE_STRING, Description: "Base64 encoded consumer key and secret",
+		IsSecret: true, isWUMUCConfigField: true},
+	{Name: "svnurl", Type: constant.CONFIG_TYPE_URL, Description: "Root of the SVN repository in which the " +
+		"updates are committed", isWUMUCConfigField: true},
+	{Name: "licenseurl", Type: constant.CONFIG_TYPE_URL, Description: "URL of the LICENSE.txt. " +
+		constant.LICENSE_URL + " environment variable takes precedence", isWUMUCConfigField: true},
+	{Name: "notacontributionurl", Type: constant.CONFIG_TYPE_URL, Description: "URL of the " +
+		"NOT_A_CONTRIBUTION.txt. " + constant.NOT_A_CONTRIBUTION_URL + " environment variable takes precedence",
+		isWUMUCConfigField: true},
+	{Name: "signingkey", Type: constant.CONFIG_TYPE_STRING, Description: "Private key used to sign the updates",
+		isWUMUCConfigField: true},
+	{Name: "trustedkeys", Type: constant.CONFIG_TYPE_LIST, Description: "Public keys used to verify the " +
+		"signatures of updates", isWUMUCConfigField: true},
+	{Name: "jiraurl", Type: constant.CONFIG_TYPE_URL, Description: "Jira used to look up the summaries of bug " +
+		"fixes", isWUMUCConfigField: true},
+	{Name: "jiratoken", Type: constant.CONFIG_TYPE_STRING, Description: "Token used to connect to the Jira",
+		IsSecret: true, isWUMUCConfigField: true},
+	{Name: "githubapiurl", Type: constant.CONFIG_TYPE_URL, Description: "GitHub API used to look up the " +
+		"summaries of GitHub issues", isWUMUCConfigField: true},
+	{Name: "githubtoken", Type: constant.CONFIG_TYPE_STRING, Description: "Token used to connect to the GitHub API",
+		IsSecret: true, isWUMUCConfigField: true},
+	{Name: constant.CHECK_MD5_DISABLED, Type: constant.CONFIG_TYPE_BOOL, Description: "Skip checking the " +
+		"checksums of the files in the distribution"},
+	{Name: constant.DETERMINISTIC_ZIP, Type: constant.CONFIG_TYPE_BOOL, Description: "Create update zips in a " +
+		"reproducible manner", IsProjectKey: true},
+	{Name: constant.RESOLVE_SYMLINKS, Type: constant.CONFIG_TYPE_BOOL, Description: "Resolve the symbolic links " +
+		"in the update directory", IsProjectKey: true},
+	{Name: constant.HASH_ALGORITHM, Type: constant.CONFIG_TYPE_STRING, Description: "Hash algorithm used to " +
+		"generate checksums", AllowedValues: []string{constant.HASH_ALGORITHM_SHA256, constant.HASH_ALGORITHM_MD5},
+		IsProjectKey: true},
+	{Name: constant.RESOURCE_FILES_MANDATORY, Type: constant.CONFIG_TYPE_LIST, Description: "Resource files " +
+		"which should be in every update"},
+	{Name: constant.RESOURCE_FILES_OPTIONAL, Type: constant.CONFIG_TYPE_LIST, Description: "Resource files " +
+		"which can be in an update"},
+	{Name: constant.RESOURCE_FILES_SKIP, Type: constant.CONFIG_TYPE_LIST, Description: "Files in the update " +
+		"directory which are not copied to the update"},
+	{Name: constant.PLATFORMS, Type: constant.CONFIG_TYPE_STRUCTURED, Description: "Platforms which updates can " +
+		"be created for", validate: func() error {
+		_, err := GetConfiguredPlatforms()
+		return err
+	}},
+	{Name: constant.PLATFORM_VERSIONS, Type: constant.CONFIG_TYPE_MAP, Description: "Legacy map of platform " +
+		"versions to platform names", IsProjectKey: true},
+	{Name: constant.PLATFORMS_URL, Type: constant.CONFIG_TYPE_URL, Description: "Endpoint used to get the " +
+		"platforms instead of the config file"},
+	{Name: constant.MAX_CLASS_VERSIONS, Type: constant.CONFIG_TYPE_MAP, Description: "Maximum class file major " +
+		"version supported by each platform version"},
+	{Name: constant.PRODUCT_CATALOG, Type: constant.CONFIG_TYPE_STRING, Description: "Location of the product " +
+		"catalog used in the offline mode"},
+	{Name: constant.HTTP_CLIENT_TIMEOUT_IN_SECONDS, Type: constant.CONFIG_TYPE_INT, Description: "Timeout of " +
+		"the requests sent to the servers"},
+	{Name: constant.HTTP_CLIENT_MAX_RETRIES, Type: constant.CONFIG_TYPE_INT, Description: "Number of times " +
+		"failed requests are retried"},
+	{Name: constant.HTTP_CLIENT_INITIAL_BACKOFF_IN_MILLISECONDS, Type: constant.CONFIG_TYPE_INT,
+		Description: "Time to wait before the first retry"},
+	{Name: constant.HTTP_CLIENT_MAX_BACKOFF_IN_SECONDS, Type: constant.CONFIG_TYPE_INT, Description: "Maximum " +
+		"time to wait between retries"},
+	{Name: constant.HTTP_CLIENT_PROXY, Type: constant.CONFIG_TYPE_URL, Description: "Proxy used to connect to " +
+		"the servers"},
+	{Name: constant.HTTP_CLIENT_CA_BUNDLE, Type: constant.CONFIG_TYPE_STRING, Description: "Additional CA " +
+		"certificates trusted when connecting to the servers"},
+	{Name: constant.CREDENTIALS_HELPER, Type: constant.CONFIG_TYPE_STRING, Description: "External program used " +
+		"to store the credentials"},
+	{Name: constant.CREDENTIALS_KEY_FILE, Type: constant.CONFIG_TYPE_STRING, Description: "Key file used to " +
+		"encrypt the credentials"},
+	{Name: constant.LINT_RULES, Type: constant.CONFIG_TYPE_STRUCTURED, Description: "Lint rules applied to the " +
+		"update descriptor and the resource files", validate: func() error {
+		rules, err := GetLintRules()
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err = validateLintRule(&rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{Name: constant.PROFILE, Type: constant.CONFIG_TYPE_STRING, Description: "Profile used if the --profile " +
+		"flag is not given", Pattern: constant.PROFILE_NAME_REGEX},
+}
+
+// This function returns the keys which can be configured in the config file.
+func GetConfigKeys() []ConfigKey {
+	return configSchema
+}
+
+// This function returns the given key of the config file. Keys are case insensitive.
+func GetConfigKey(name string) (*ConfigKey, error) {
+	for i := range configSchema {
+		if strings.EqualFold(configSchema[i].Name, name) {
+			return &configSchema[i], nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("unknown configuration key '%s'. Run 'wum-uc config list' to see the "+
+		"available keys", name))
+}
+
+// This function returns the environment variable which overrides the value of the key.
+func (key *ConfigKey) GetEnvName() string {
+	return constant.WUMUC_ENV_PREFIX + "_" + strings.Replace(strings.ToUpper(key.Name), ".", "_", -1)
+}
+
+// This function returns the current value of the key and where it is taken from.
+func (key *ConfigKey) GetValue() (interface{}, string) {
+	_, isEnvSet := os.LookupEnv(key.GetEnvName())
+	if !key.isWUMUCConfigField {
+		switch {
+		case isEnvSet:
+			return viper.Get(key.Name), constant.CONFIG_SOURCE_ENVIRONMENT
+		case viper.InConfig(key.Name):
+			return viper.Get(key.Name), constant.CONFIG_SOURCE_FILE
+		}
+		return viper.Get(key.Name), constant.CONFIG_SOURCE_DEFAULT
+	}
+	config := GetWUMUCConfigs()
+	value := getYAMLField(config, key.Name).Interface()
+	profileValue := reflect.Value{}
+	if profile := config.Profiles[config.profile]; len(config.profile) > 0 && profile != nil {
+		profileValue = getYAMLField(profile, key.Name)
+	}
+	switch {
+	case isEnvSet:
+		return value, constant.CONFIG_SOURCE_ENVIRONMENT
+	case profileValue.IsValid() && !isEmptyValue(profileValue.Interface()):
+		return value, constant.CONFIG_SOURCE_PROFILE
+	case !isEmptyValue(value):
+		return value, constant.CONFIG_SOURCE_FILE
+	}
+	return value, constant.CONFIG_SOURCE_DEFAULT
+}
+
+// This function will convert the given command line arguments to a value of the key. Lists are given as separate
+// arguments and maps are given as key=value arguments.
+func (key *ConfigKey) ParseValue(args []string) (interface{}, error) {
+	var value interface{}
+	switch key.Type {
+	case constant.CONFIG_TYPE_STRUCTURED:
+		return nil, errors.New(fmt.Sprintf("'%s' cannot be set using this command. Edit the config file '%s' "+
+			"instead", key.Name, key.getConfigFilePath()))
+	case constant.CONFIG_TYPE_LIST:
+		value = args
+	case constant.CONFIG_TYPE_MAP:
+		values := make(map[string]string)
+		for _, arg := range args {
+			parts := strings.SplitN(arg, "=", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 {
+				return nil, errors.New(fmt.Sprintf("invalid value '%s' found for '%s'. Values of maps should be "+
+					"given as key=value", arg, key.Name))
+			}
+			values[parts[0]] = parts[1]
+		}
+		value = values
+	default:
+		if len(args) != 1 {
+			return nil, errors.New(fmt.Sprintf("'%s' accepts a single value, found %d", key.Name, len(args)))
+		}
+		value = args[0]
+		var err error
+		switch key.Type {
+		case constant.CONFIG_TYPE_BOOL:
+			value, err = strconv.ParseBool(args[0])
+		case constant.CONFIG_TYPE_INT:
+			value, err = strconv.Atoi(args[0])
+		}
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid value '%s' found for '%s'. Expected a value of type %s",
+				args[0], key.Name, key.Type))
+		}
+	}
+	if err := key.Validate(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// This function will validate the given value of the key. Values given using environment variables are strings, so
+// strings are accepted for all the types.
+func (key *ConfigKey) Validate(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	invalidValueError := errors.New(fmt.Sprintf("invalid value '%v' found for '%s'. Expected a value of type %s",
+		value, key.Name, key.Type))
+	stringValue, isString := value.(string)
+	switch key.Type {
+	case constant.CONFIG_TYPE_STRUCTURED:
+		if key.validate != nil {
+			return key.validate()
+		}
+	case constant.CONFIG_TYPE_BOOL:
+		if _, err := strconv.ParseBool(fmt.Sprint(value)); err != nil {
+			return invalidValueError
+		}
+	case constant.CONFIG_TYPE_INT:
+		number, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil || number < 0 {
+			return invalidValueError
+		}
+	case constant.CONFIG_TYPE_LIST:
+		if kind := reflect.ValueOf(value).Kind(); !isString && kind != reflect.Slice {
+			return invalidValueError
+		}
+	case constant.CONFIG_TYPE_MAP:
+		if isString {
+			if json.Unmarshal([]byte(stringValue), &map[string]string{}) != nil {
+				return invalidValueError
+			}
+		} else if reflect.ValueOf(value).Kind() != reflect.Map {
+			return invalidValueError
+		}
+	case constant.CONFIG_TYPE_URL:
+		if !isString {
+			return invalidValueError
+		}
+		parsedURL, err := url.Parse(stringValue)
+		if len(stringValue) > 0 && (err != nil || len(parsedURL.Host) == 0 ||
+			(parsedURL.Scheme != "http" && parsedURL.Scheme != "https")) {
+			return errors.New(fmt.Sprintf("invalid URL '%s' found for '%s'. Expected an http or https URL",
+				stringValue, key.Name))
+		}
+	default:
+		if !isString {
+			return invalidValueError
+		}
+		if len(key.AllowedValues) > 0 && !IsStringIsInSlice(stringValue, key.AllowedValues) {
+			return errors.New(fmt.Sprintf("invalid value '%s' found for '%s'. Allowed values are %v", stringValue,
+				key.Name, key.AllowedValues))
+		}
+		if len(key.Pattern) > 0 && len(stringValue) > 0 && !regexp.MustCompile(key.Pattern).MatchString(stringValue) {
+			return errors.New(fmt.Sprintf("invalid value '%s' found for '%s'. It should match the regex '%s'",
+				stringValue, key.Name, key.Pattern))
+		}
+	}
+	return nil
+}
+
+// This function will validate the current values of all the keys. Errors of all the invalid keys are returned.
+func ValidateConfig() []error {
+	errs := make([]error, 0)
+	for i := range configSchema {
+		value, _ := configSchema[i].GetValue()
+		if err := configSchema[i].Validate(value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// This function will write the given value of the key to the config file and return the path of the file. Values of
+// the WUMUCConfig are written to the selected profile, if the profile has the key.
+func SetConfigValue(key *ConfigKey, value interface{}) (string, error) {
+	configFilePath := key.getConfigFilePath()
+	path := strings.Split(key.Name, ".")
+	if profile := GetWUMUCConfigs().profile; key.isWUMUCConfigField && len(profile) > 0 &&
+		getYAMLField(&WUMUCProfile{}, key.Name).IsValid() {
+		path = []string{"profiles", profile, key.Name}
+	}
+	unlock, err := lockConfigFile(configFilePath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	items := yaml.MapSlice{}
+	data, err := ioutil.ReadFile(configFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err = yaml.Unmarshal(data, &items); err != nil {
+		return "", errors.New(fmt.Sprintf("error occurred while reading '%s'. %v", configFilePath, err))
+	}
+	logger.Debug(fmt.Sprintf("Setting %s in %s", strings.Join(path, "."), configFilePath))
+	if data, err = yaml.Marshal(setYAMLValue(items, path, value)); err != nil {
+		return "", err
+	}
+	return configFilePath, writeFileAtomically(configFilePath, data)
+}
+
+// This function returns the config file in which the key is written. Keys read using viper are written to the
+// config file read by viper, if one is found.
+func (key *ConfigKey) getConfigFilePath() string {
+	if configFileUsed := viper.ConfigFileUsed(); !key.isWUMUCConfigField && len(configFileUsed) > 0 {
+		return configFileUsed
+	}
+	return wumucConfigFilePath
+}
+
+// This function will set the value in the given path of the yaml. Keys are matched case insensitively, so that the
+// existing keys read using viper are updated instead of adding new keys.
+func setYAMLValue(items yaml.MapSlice, path []string, value interface{}) yaml.MapSlice {
+	for i, item := range items {
+		if name, ok := item.Key.(string); ok && strings.EqualFold(name, path[0]) {
+			if len(path) == 1 {
+				items[i].Value = value
+			} else {
+				children, _ := item.Value.(yaml.MapSlice)
+				items[i].Value = setYAMLValue(children, path[1:], value)
+			}
+			return items
+		}
+	}
+	if len(path) == 1 {
+		return append(items, yaml.MapItem{Key: path[0], Value: value})
+	}
+	return append(items, yaml.MapItem{Key: path[0], Value: setYAMLValue(yaml.MapSlice{}, path[1:], value)})
+}
+
+// This function will override the values of the WUMUCConfig using the environment variables of the keys.
+func (wumucConfig *WUMUCConfig) applyEnvironmentOverrides() {
+	for _, key := range configSchema {
+		value, exists := os.LookupEnv(key.GetEnvName())
+		if !key.isWUMUCConfigField || !exists {
+			continue
+		}
+		logger.Debug(fmt.Sprintf("Using the value of %s from the environment variable %s", key.Name,
+			key.GetEnvName()))
+		field := getYAMLField(wumucConfig, key.Name)
+		if !field.IsValid() || !field.CanSet() {
+			logger.Debug(fmt.Sprintf("Field of %s not found in the configurations", key.Name))
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Slice:
+			field.Set(reflect.ValueOf(strings.Fields(value)))
+		case reflect.String:
+			field.SetString(value)
+		default:
+			logger.Debug(fmt.Sprintf("Value of %s cannot be set from an environment variable", key.Name))
+		}
+	}
+}
+
+// This function returns the field of the given struct pointer which has the given yaml key. Zero value is returned if
+// the field is not found.
+func getYAMLField(structPointer interface{}, yamlKey string) reflect.Value {
+	structValue := reflect.ValueOf(structPointer).Elem()
+	for i := 0; i < structValue.NumField(); i++ {
+		if getYAMLKey(structValue.Type().Field(i)) == yamlKey {
+			return structValue.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// This function returns the yaml keys of the exported fields of the given struct pointer.
+func getYAMLKeys(structPointer interface{}) []string {
+	structType := reflect.TypeOf(structPointer).Elem()
+	keys := make([]string, 0)
+	for i := 0; i < structType.NumField(); i++ {
+		if len(structType.Field(i).PkgPath) == 0 {
+			keys = append(keys, getYAMLKey(structType.Field(i)))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// This function returns the key which is used by yaml for the given field.
+func getYAMLKey(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("yaml"), ",")[0]; len(name) > 0 {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// This function checks whether the given value is empty, ie. an empty string or an empty list.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return reflect.ValueOf(value).Len() == 0
+	}
+	return false
+}
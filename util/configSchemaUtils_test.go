@@ -0,0 +1,197 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestParseConfigValue(t *testing.T) {
+	tests := []struct {
+		key      string
+		args     []string
+		expected interface{}
+	}{
+		{"hash_algorithm", []string{"md5"}, "md5"},
+		{constant.DETERMINISTIC_ZIP, []string{"false"}, false},
+		{constant.HTTP_CLIENT_MAX_RETRIES, []string{"5"}, 5},
+		{constant.RESOURCE_FILES_SKIP, []string{"README.txt", "NOTES.txt"}, []string{"README.txt", "NOTES.txt"}},
+		{constant.MAX_CLASS_VERSIONS, []string{"4.4.0=52"}, map[string]string{"4.4.0": "52"}},
+		{"serverurl", []string{"https://localhost:9443"}, "https://localhost:9443"},
+		{constant.PROFILE, []string{"staging"}, "staging"},
+	}
+	for _, test := range tests {
+		key, err := GetConfigKey(test.key)
+		if err != nil {
+			t.Fatalf("Test failed, unexpected error: %v", err)
+		}
+		if value, err := key.ParseValue(test.args); err != nil || !reflect.DeepEqual(value, test.expected) {
+			t.Errorf("Test failed for %s, expected: %v, actual: %v, error: %v", test.key, test.expected, value, err)
+		}
+	}
+
+	invalidValues := []struct {
+		key  string
+		args []string
+	}{
+		{constant.HASH_ALGORITHM, []string{"sha1"}},
+		{constant.HASH_ALGORITHM, []string{"md5", "sha256"}},
+		{constant.DETERMINISTIC_ZIP, []string{"maybe"}},
+		{constant.HTTP_CLIENT_MAX_RETRIES, []string{"-1"}},
+		{constant.MAX_CLASS_VERSIONS, []string{"52"}},
+		{"serverurl", []string{"localhost:9443"}},
+		{constant.PROFILE, []string{"../staging"}},
+		{constant.PLATFORMS, []string{"wilkes"}},
+	}
+	for _, test := range invalidValues {
+		key, err := GetConfigKey(test.key)
+		if err != nil {
+			t.Fatalf("Test failed, unexpected error: %v", err)
+		}
+		if value, err := key.ParseValue(test.args); err == nil {
+			t.Errorf("Test failed, expected an error for %s %v, found: %v", test.key, test.args, value)
+		}
+	}
+	if _, err := GetConfigKey("unknown"); err == nil {
+		t.Error("Test failed, expected an error for an unknown key")
+	}
+}
+
+func TestSetConfigValue(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer setEnv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "")()
+	previousConfig, previousConfigFilePath := wumucConfig, wumucConfigFilePath
+	defer func() { wumucConfig, wumucConfigFilePath = previousConfig, previousConfigFilePath }()
+
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	if err = ioutil.WriteFile(configFile, []byte(testProfilesConfig+"resource_files:\n  mandatory:\n"+
+		"  - LICENSE.txt\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	viper.SetConfigFile(configFile)
+	if err = viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	LoadWUMUCConfig(tempDir)
+
+	for name, value := range map[string]interface{}{
+		constant.RESOURCE_FILES_SKIP: []string{"README.txt"},
+		constant.HASH_ALGORITHM:      "md5",
+		"jiraurl":                    "https://jira.example.com",
+	} {
+		key, _ := GetConfigKey(name)
+		if path, err := SetConfigValue(key, value); err != nil || path != configFile {
+			t.Fatalf("Test failed, unexpected path: %s, error: %v", path, err)
+		}
+	}
+	if err = viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if skip := viper.GetStringSlice(constant.RESOURCE_FILES_SKIP); !reflect.DeepEqual(skip, []string{"README.txt"}) ||
+		viper.GetString(constant.HASH_ALGORITHM) != "md5" ||
+		len(viper.GetStringSlice(constant.RESOURCE_FILES_MANDATORY)) != 1 {
+		t.Errorf("Test failed, unexpected values: %v", viper.AllSettings())
+	}
+
+	// Keys read using viper should be kept when wum-uc writes the config.yaml
+	config := LoadWUMUCConfig(tempDir)
+	if config.JiraURL != "https://jira.example.com" || config.Profiles["staging"] == nil {
+		t.Errorf("Test failed, unexpected config: %v", config)
+	}
+	config.Username = "another@wso2.com"
+	if err = WriteConfigFile(config, configFile); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil || !strings.Contains(string(data), "HASH_ALGORITHM: md5") ||
+		!strings.Contains(string(data), "username: another@wso2.com") ||
+		!strings.Contains(string(data), "mandatory:") {
+		t.Errorf("Test failed, keys not kept in the config.yaml: %s, error: %v", string(data), err)
+	}
+}
+
+func TestEnvironmentOverrides(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer setEnv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "")()
+	defer setEnv("WUMUC_SERVERURL", "https://env.example.com")()
+	defer setEnv("WUMUC_TRUSTEDKEYS", "first.asc second.asc")()
+	previousConfig, previousConfigFilePath := wumucConfig, wumucConfigFilePath
+	defer func() { wumucConfig, wumucConfigFilePath = previousConfig, previousConfigFilePath }()
+
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	if err = ioutil.WriteFile(configFile, []byte(testProfilesConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config := LoadWUMUCConfig(tempDir)
+	if config.ServerURL != "https://env.example.com" ||
+		!reflect.DeepEqual(config.TrustedKeys, []string{"first.asc", "second.asc"}) {
+		t.Errorf("Test failed, environment variables not applied: %v", config)
+	}
+	key, _ := GetConfigKey("serverurl")
+	if value, source := key.GetValue(); value != "https://env.example.com" ||
+		source != constant.CONFIG_SOURCE_ENVIRONMENT {
+		t.Errorf("Test failed, unexpected value: %v, source: %s", value, source)
+	}
+
+	// Values given using environment variables should not be persisted
+	if err = WriteConfigFile(config, configFile); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil || strings.Contains(string(data), "env.example.com") || strings.Contains(string(data), "first.asc") {
+		t.Errorf("Test failed, environment variables persisted: %s, error: %v", string(data), err)
+	}
+
+	defer setEnv("WUMUC_JIRAURL", "jira.example.com")()
+	LoadWUMUCConfig(tempDir)
+	if errs := ValidateConfig(); len(errs) != 1 || !strings.Contains(errs[0].Error(), "jiraurl") {
+		t.Errorf("Test failed, unexpected validation errors: %v", errs)
+	}
+}
+
+func TestWUMUCConfigFieldsOfSchema(t *testing.T) {
+	// Values of these keys are read from the WUMUCConfig, so each of them should have a settable field
+	for _, key := range configSchema {
+		if !key.isWUMUCConfigField {
+			continue
+		}
+		field := getYAMLField(&WUMUCConfig{}, key.Name)
+		if !field.IsValid() || !field.CanSet() {
+			t.Errorf("Test failed, field not found in the WUMUCConfig for '%s'", key.Name)
+			continue
+		}
+		if kind := field.Kind(); kind != reflect.String && kind != reflect.Slice {
+			t.Errorf("Test failed, unsupported type %v of the field for '%s'", kind, key.Name)
+		}
+	}
+}
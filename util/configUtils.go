@@ -22,6 +22,7 @@ package util
 import (
 	"errors"
 	"fmt"
+	"github.com/spf13/viper"
 	"github.com/wso2/update-creator-tool/constant"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
@@ -30,13 +31,44 @@ import (
 )
 
 type WUMUCConfig struct {
-	Username     string
-	ServerURL    string
-	TokenURL     string
-	VersionURL   string
-	AppKey       string
-	RefreshToken string
-	AccessToken  string
+	Username   string
+	ServerURL  string
+	TokenURL   string
+	VersionURL string
+	AppKey     string
+	// Tokens are kept in the credential store. They are found in the config.yaml only if it was written by an older
+	// version of wum-uc, in which case they are moved to the credential store
+	RefreshToken string `yaml:"refreshtoken,omitempty"`
+	AccessToken  string `yaml:"accesstoken,omitempty"`
+	// Lifetime of the access token in seconds and the unix time at which it was issued
+	ExpiresIn     int   `yaml:"expiresin,omitempty"`
+	TokenIssuedAt int64 `yaml:"tokenissuedat,omitempty"`
+	// Token revocation API. If not configured, it is derived from the TokenURL
+	RevokeURL string `yaml:"revokeurl,omitempty"`
+	// OAuth2 client used to authenticate without a WSO2 account. Client secret is kept in the credential store
+	ClientID     string `yaml:"clientid,omitempty"`
+	ClientSecret string `yaml:"clientsecret,omitempty"`
+	// Set if the credentials are given using environment variables, in which case they are not persisted
+	credentialsFromEnvironment bool
+	// Root of the SVN repository in which the updates are committed
+	SVNURL string `yaml:"svnurl,omitempty"`
+	// URLs of the resource files copied to the updates. Environment variables take precedence over them
+	LicenseURL          string `yaml:"licenseurl,omitempty"`
+	NotAContributionURL string `yaml:"notacontributionurl,omitempty"`
+	// Named profiles which can be selected using the --profile flag or the WUMUC_PROFILE environment variable
+	Profiles map[string]*WUMUCProfile `yaml:"profiles,omitempty"`
+	// Name of the selected profile
+	profile string
+	// Private key used to sign the created updates
+	SigningKey string `yaml:"signingkey,omitempty"`
+	// Public keys used to verify the signatures of updates
+	TrustedKeys []string `yaml:"trustedkeys,omitempty"`
+	// Jira used to look up the summaries of bug fixes. Jira keys are not looked up if the URL is not configured
+	JiraURL   string `yaml:"jiraurl,omitempty"`
+	JiraToken string `yaml:"jiratoken,omitempty"`
+	// GitHub API used to look up the summaries of GitHub issues
+	GitHubAPIURL string `yaml:"githubapiurl,omitempty"`
+	GitHubToken  string `yaml:"githubtoken,omitempty"`
 }
 
 var wumucConfig WUMUCConfig
@@ -61,19 +93,39 @@ func LoadWUMUCConfig(wumucLocalRepo string) *WUMUCConfig {
 			AppKey:     constant.BASE64_ENCODED_CONSUMER_KEY_AND_SECRET,
 		}
 
-		// Write the wum-uc configuration to the config file.
-		WriteConfigFile(&wumucConfig, wumucConfigFilePath)
+		// Write the wum-uc configuration to the config file. wum-uc home is not created yet when wum-uc is run for
+		// the first time.
+		if err = os.MkdirAll(filepath.Dir(wumucConfigFilePath), 0700); err != nil {
+			HandleErrorAndExit(err, fmt.Sprintf("Error occurred while creating the %v directory.",
+				filepath.Dir(wumucConfigFilePath)))
+		}
+		if err = WriteConfigFile(&wumucConfig, wumucConfigFilePath); err != nil {
+			HandleErrorAndExit(err, fmt.Sprintf("Error occurred while writing the %v file.", wumucConfigFilePath))
+		}
 		return &wumucConfig
 	} else {
-		data, err := ioutil.ReadFile(wumucConfigFilePath)
-		if err != nil {
-			HandleErrorAndExit(err)
-		}
-
-		err = yaml.Unmarshal(data, &wumucConfig)
+		config, hasPlainTextTokens, err := readConfigFile(wumucConfigFilePath, "")
 		if err != nil {
 			HandleErrorAndExit(err, fmt.Sprintf("unable to load wum-uc configuration from '%v'.", wumucConfigFilePath))
 		}
+		wumucConfig = *config
+		if hasPlainTextTokens {
+			logger.Info("Moving the tokens in config.yaml to the credential store")
+			if err = WriteConfigFile(&wumucConfig, wumucConfigFilePath); err != nil {
+				HandleErrorAndExit(err, "Error occurred while moving the tokens to the credential store.")
+			}
+		}
+		if profile := viper.GetString(constant.PROFILE); len(profile) > 0 {
+			logger.Debug(fmt.Sprintf("Using the profile '%s'", profile))
+			if config, _, err = readConfigFile(wumucConfigFilePath, profile); err != nil {
+				HandleErrorAndExit(err)
+			}
+			wumucConfig = *config
+		}
+		wumucConfig.applyEnvironmentOverrides()
+		if err = wumucConfig.applyEnvironmentCredentials(); err != nil {
+			HandleErrorAndExit(err)
+		}
 
 		// Validate config.yaml
 		wumucConfig.validate()
@@ -95,28 +147,128 @@ func SetWUMUCLocalRepo(localRepoPath string) {
 	}
 }
 
-// Write wum-uc configuration to the config file.
+// Read the wum-uc configuration in the given config file along with the tokens in the credential store. If a profile is
+// given, values of the profile are used instead of the top level values. If the config file contains plain text tokens
+// written by an older version of wum-uc, they are returned instead and the returned flag is set, so that they can be
+// moved to the credential store. An error is returned if the credential store cannot be read, as the stored
+// credentials would otherwise be overwritten or erased when the configuration is written.
+func readConfigFile(wumucConfigFilePath, profile string) (*WUMUCConfig, bool, error) {
+	config, err := unmarshalConfigFile(wumucConfigFilePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(profile) > 0 {
+		if err = config.selectProfile(profile); err != nil {
+			return nil, false, err
+		}
+	} else if *config.getCredentials() != (Credentials{}) {
+		return config, true, nil
+	}
+	credentials, err := GetCredentialStore(filepath.Dir(wumucConfigFilePath), profile).Get(config)
+	if err != nil {
+		return nil, false, errors.New(fmt.Sprintf("unable to read the stored credentials. %v", err))
+	}
+	if credentials != nil {
+		config.setCredentials(credentials)
+	}
+	return config, false, nil
+}
+
+// Read the values in the given config file as they are.
+func unmarshalConfigFile(wumucConfigFilePath string) (*WUMUCConfig, error) {
+	data, err := ioutil.ReadFile(wumucConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+	config := WUMUCConfig{}
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Returns the wum-uc configuration as it is in the config.yaml, without selecting a profile or loading the credentials.
+func GetStoredWUMUCConfigs() (*WUMUCConfig, error) {
+	return unmarshalConfigFile(wumucConfigFilePath)
+}
+
+// Returns the path of the config.yaml in the wum-uc home.
+func GetWUMUCConfigFilePath() string {
+	return wumucConfigFilePath
+}
+
+// Write wum-uc configuration to the config file. Tokens are written to the credential store instead of the config
+// file. If the config file exists, only the values changed by wum-uc (the username and the client id) are written, so
+// that the values overridden using the profile or the environment variables are not persisted. Keys which are read
+// using viper are kept as they are. Configuration is written to a temporary file first and then moved, so that other
+// wum-uc processes never read a partially written config file.
 func WriteConfigFile(wumucConfig *WUMUCConfig, wumucConfigFilePath string) error {
 	logger.Debug(fmt.Sprintf("Writing wum-uc configs to %s file", wumucConfigFilePath))
-	data, err := yaml.Marshal(wumucConfig)
+	credentialStore := GetCredentialStore(filepath.Dir(wumucConfigFilePath), wumucConfig.profile)
+	var err error
+	if *wumucConfig.getCredentials() != (Credentials{}) {
+		err = credentialStore.Store(wumucConfig, wumucConfig.getCredentials())
+	} else {
+		err = credentialStore.Erase(wumucConfig)
+	}
 	if err != nil {
-		return err
+		return errors.New(fmt.Sprintf("error occurred while writing the credentials. %v", err))
+	}
+	configWithoutCredentials := *wumucConfig
+	otherKeys := yaml.MapSlice{}
+	if exists, _ := IsFileExists(wumucConfigFilePath); exists {
+		fileConfig, err := unmarshalConfigFile(wumucConfigFilePath)
+		if err != nil {
+			return err
+		}
+		if len(wumucConfig.profile) > 0 {
+			if err = wumucConfig.updateProfile(fileConfig); err != nil {
+				return err
+			}
+		} else {
+			fileConfig.Username = wumucConfig.Username
+			fileConfig.ClientID = wumucConfig.ClientID
+		}
+		configWithoutCredentials = *fileConfig
+		if otherKeys, err = getOtherConfigKeys(wumucConfigFilePath); err != nil {
+			return err
+		}
 	}
-	// Open a new file for writing only
-	file, err := os.OpenFile(
-		wumucConfigFilePath,
-		os.O_WRONLY|os.O_TRUNC|os.O_CREATE,
-		0600,
-	)
+	configWithoutCredentials.setCredentials(&Credentials{})
+	data, err := yaml.Marshal(&configWithoutCredentials)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	_, err = file.Write(data)
+	if len(otherKeys) > 0 {
+		items := yaml.MapSlice{}
+		if err = yaml.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		if data, err = yaml.Marshal(append(items, otherKeys...)); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomically(wumucConfigFilePath, data)
+}
+
+// Returns the keys in the given config file which are not fields of the WUMUCConfig, ie. the keys read using viper.
+func getOtherConfigKeys(wumucConfigFilePath string) (yaml.MapSlice, error) {
+	data, err := ioutil.ReadFile(wumucConfigFilePath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	items := yaml.MapSlice{}
+	if err = yaml.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	wumucConfigKeys := getYAMLKeys(&WUMUCConfig{})
+	otherKeys := yaml.MapSlice{}
+	for _, item := range items {
+		if name, ok := item.Key.(string); !ok || !IsStringIsInSlice(name, wumucConfigKeys) {
+			otherKeys = append(otherKeys, item)
+		}
 	}
-	return nil
+	return otherKeys, nil
 }
 
 // Validate wum-uc configurations
@@ -0,0 +1,368 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+)
+
+// Tokens and the client secret which are kept in the credential store instead of the config.yaml.
+type Credentials struct {
+	AccessToken   string `yaml:"accesstoken"`
+	RefreshToken  string `yaml:"refreshtoken"`
+	ExpiresIn     int    `yaml:"expiresin,omitempty"`
+	TokenIssuedAt int64  `yaml:"tokenissuedat,omitempty"`
+	ClientSecret  string `yaml:"clientsecret,omitempty"`
+}
+
+// Store which keeps the credentials of the given wum-uc configuration. Get returns nil if there are no stored
+// credentials.
+type CredentialStore interface {
+	Get(wumucConfig *WUMUCConfig) (*Credentials, error)
+	Store(wumucConfig *WUMUCConfig, credentials *Credentials) error
+	Erase(wumucConfig *WUMUCConfig) error
+}
+
+// This function returns the credential store which should be used with the given profile of the config.yaml in the
+// given wum-uc home. An external credential helper is used if it is configured, or else the credentials are encrypted
+// and stored in the wum-uc home.
+func GetCredentialStore(wumucHome, profile string) CredentialStore {
+	if helper := viper.GetString(constant.CREDENTIALS_HELPER); len(helper) > 0 {
+		return &HelperCredentialStore{Command: helper, Profile: profile}
+	}
+	keyFile := viper.GetString(constant.CREDENTIALS_KEY_FILE)
+	if len(keyFile) == 0 {
+		keyFile = filepath.Join(wumucHome, constant.WUMUC_CREDENTIALS_KEY_FILE)
+	}
+	return &FileCredentialStore{
+		FilePath:    filepath.Join(wumucHome, getCredentialsFileName(profile)),
+		KeyFilePath: keyFile,
+		Passphrase:  os.Getenv(constant.WUMUC_CREDENTIALS_PASSPHRASE),
+	}
+}
+
+// This function returns the credentials in the given configuration.
+func (wumucConfig *WUMUCConfig) getCredentials() *Credentials {
+	return &Credentials{
+		AccessToken:   wumucConfig.AccessToken,
+		RefreshToken:  wumucConfig.RefreshToken,
+		ExpiresIn:     wumucConfig.ExpiresIn,
+		TokenIssuedAt: wumucConfig.TokenIssuedAt,
+		ClientSecret:  wumucConfig.ClientSecret,
+	}
+}
+
+// This function will set the given credentials in the configuration.
+func (wumucConfig *WUMUCConfig) setCredentials(credentials *Credentials) {
+	wumucConfig.AccessToken = credentials.AccessToken
+	wumucConfig.RefreshToken = credentials.RefreshToken
+	wumucConfig.ExpiresIn = credentials.ExpiresIn
+	wumucConfig.TokenIssuedAt = credentials.TokenIssuedAt
+	wumucConfig.ClientSecret = credentials.ClientSecret
+}
+
+// Credential store which keeps the credentials in a file encrypted using AES-GCM. Key is derived from the passphrase
+// using scrypt if a passphrase is given, or else it is read from a machine local key file which is generated when the
+// credentials are stored for the first time. By default, the key file is kept next to the credentials file, so it only
+// protects the credentials when the credentials file is copied without the key file (ie. in backups).
+type FileCredentialStore struct {
+	FilePath    string
+	KeyFilePath string
+	Passphrase  string
+}
+
+// Content of the encrypted credentials file.
+type encryptedCredentials struct {
+	KeyDerivation string `yaml:"keyderivation"`
+	Salt          string `yaml:"salt,omitempty"`
+	Nonce         string `yaml:"nonce"`
+	Data          string `yaml:"data"`
+}
+
+// This function will read and decrypt the credentials in the credentials file.
+func (store *FileCredentialStore) Get(wumucConfig *WUMUCConfig) (*Credentials, error) {
+	data, err := ioutil.ReadFile(store.FilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	encrypted := encryptedCredentials{}
+	if err = yaml.Unmarshal(data, &encrypted); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid credentials file '%s'. %v", store.FilePath, err))
+	}
+	salt, err := base64.StdEncoding.DecodeString(encrypted.Salt)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid credentials file '%s'. %v", store.FilePath, err))
+	}
+	key, err := store.getKey(encrypted.KeyDerivation, salt, false)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(encrypted.Nonce)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid credentials file '%s'. %v", store.FilePath, err))
+	}
+	cipherText, err := base64.StdEncoding.DecodeString(encrypted.Data)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid credentials file '%s'. %v", store.FilePath, err))
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New(fmt.Sprintf("invalid credentials file '%s'. Invalid nonce", store.FilePath))
+	}
+	plainText, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to decrypt the credentials in '%s'. Check whether the %s "+
+			"environment variable or the key file is correct", store.FilePath, constant.WUMUC_CREDENTIALS_PASSPHRASE))
+	}
+	credentials := Credentials{}
+	if err = yaml.Unmarshal(plainText, &credentials); err != nil {
+		return nil, err
+	}
+	return &credentials, nil
+}
+
+// This function will encrypt the given credentials and write them to the credentials file.
+func (store *FileCredentialStore) Store(wumucConfig *WUMUCConfig, credentials *Credentials) error {
+	plainText, err := yaml.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+	encrypted := encryptedCredentials{KeyDerivation: constant.CREDENTIALS_KEY_DERIVATION_KEY_FILE}
+	var salt []byte
+	if len(store.Passphrase) > 0 {
+		encrypted.KeyDerivation = constant.CREDENTIALS_KEY_DERIVATION_SCRYPT
+		if salt, err = generateRandomBytes(16); err != nil {
+			return err
+		}
+		encrypted.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+	key, err := store.getKey(encrypted.KeyDerivation, salt, true)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce, err := generateRandomBytes(gcm.NonceSize())
+	if err != nil {
+		return err
+	}
+	encrypted.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	encrypted.Data = base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plainText, nil))
+	data, err := yaml.Marshal(encrypted)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(store.FilePath, data)
+}
+
+// This function will remove the credentials file. Key file is kept, so that it can be used with the next login.
+func (store *FileCredentialStore) Erase(wumucConfig *WUMUCConfig) error {
+	if err := os.Remove(store.FilePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// This function returns the key used to encrypt the credentials. If the key file does not exist, a new key is
+// generated only if the given create flag is set.
+func (store *FileCredentialStore) getKey(keyDerivation string, salt []byte, create bool) ([]byte, error) {
+	switch keyDerivation {
+	case constant.CREDENTIALS_KEY_DERIVATION_SCRYPT:
+		if len(store.Passphrase) == 0 {
+			return nil, errors.New(fmt.Sprintf("credentials in '%s' are encrypted using a passphrase. Set the "+
+				"passphrase in the %s environment variable", store.FilePath, constant.WUMUC_CREDENTIALS_PASSPHRASE))
+		}
+		return scrypt.Key([]byte(store.Passphrase), salt, 32768, 8, 1, 32)
+	case constant.CREDENTIALS_KEY_DERIVATION_KEY_FILE:
+		key, err := ioutil.ReadFile(store.KeyFilePath)
+		if os.IsNotExist(err) && create {
+			logger.Debug(fmt.Sprintf("Generating the key file %s", store.KeyFilePath))
+			if key, err = generateRandomBytes(32); err != nil {
+				return nil, err
+			}
+			return key, writeFileAtomically(store.KeyFilePath, key)
+		}
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("unable to read the key file '%s'. %v", store.KeyFilePath, err))
+		}
+		if len(key) != 32 {
+			return nil, errors.New(fmt.Sprintf("invalid key file '%s'. It should contain 32 bytes",
+				store.KeyFilePath))
+		}
+		return key, nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported key derivation '%s' found in '%s'", keyDerivation,
+			store.FilePath))
+	}
+}
+
+// This function returns the AES-GCM cipher for the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// This function returns the given number of cryptographically secure random bytes.
+func generateRandomBytes(length int) ([]byte, error) {
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rand.Reader, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// This function will write the given data to a temporary file readable only by the owner and then move it to the
+// given location.
+func writeFileAtomically(location string, data []byte) error {
+	tempFilePath := location + ".tmp"
+	if err := ioutil.WriteFile(tempFilePath, data, 0600); err != nil {
+		os.Remove(tempFilePath)
+		return err
+	}
+	return os.Rename(tempFilePath, location)
+}
+
+// Credential store which delegates to an external credential helper command, in the same way as git credential
+// helpers. The helper is invoked with 'get', 'store' or 'erase' as the last argument and the attributes are written
+// to its standard input as key=value lines. For 'get', the helper should write the stored attributes to its standard
+// output in the same format.
+type HelperCredentialStore struct {
+	Command string
+	// Profile of the credentials, which is sent to the helper if it is not empty
+	Profile string
+}
+
+// This function will get the credentials from the credential helper.
+func (store *HelperCredentialStore) Get(wumucConfig *WUMUCConfig) (*Credentials, error) {
+	output, err := store.invoke("get", store.getAttributes(wumucConfig))
+	if err != nil {
+		return nil, err
+	}
+	attributes := parseCredentialAttributes(output)
+	credentials := Credentials{
+		AccessToken:  attributes["access_token"],
+		RefreshToken: attributes["refresh_token"],
+		ClientSecret: attributes["client_secret"],
+	}
+	if credentials == (Credentials{}) {
+		return nil, nil
+	}
+	credentials.ExpiresIn, _ = strconv.Atoi(attributes["expires_in"])
+	credentials.TokenIssuedAt, _ = strconv.ParseInt(attributes["token_issued_at"], 10, 64)
+	return &credentials, nil
+}
+
+// This function will send the given credentials to the credential helper to be stored.
+func (store *HelperCredentialStore) Store(wumucConfig *WUMUCConfig, credentials *Credentials) error {
+	attributes := store.getAttributes(wumucConfig)
+	attributes = append(attributes,
+		"access_token="+credentials.AccessToken,
+		"refresh_token="+credentials.RefreshToken,
+		"expires_in="+strconv.Itoa(credentials.ExpiresIn),
+		"token_issued_at="+strconv.FormatInt(credentials.TokenIssuedAt, 10),
+		"client_secret="+credentials.ClientSecret)
+	_, err := store.invoke("store", attributes)
+	return err
+}
+
+// This function will ask the credential helper to remove the stored credentials.
+func (store *HelperCredentialStore) Erase(wumucConfig *WUMUCConfig) error {
+	_, err := store.invoke("erase", store.getAttributes(wumucConfig))
+	return err
+}
+
+// This function returns the attributes which identify the credentials of the given configuration.
+func (store *HelperCredentialStore) getAttributes(wumucConfig *WUMUCConfig) []string {
+	attributes := []string{}
+	if tokenURL, err := url.Parse(wumucConfig.TokenURL); err == nil && len(tokenURL.Host) > 0 {
+		attributes = append(attributes, "protocol="+tokenURL.Scheme, "host="+tokenURL.Host)
+	}
+	if len(store.Profile) > 0 {
+		attributes = append(attributes, "profile="+store.Profile)
+	}
+	if len(wumucConfig.ClientID) > 0 {
+		attributes = append(attributes, "username="+wumucConfig.ClientID)
+	} else if len(wumucConfig.Username) > 0 {
+		attributes = append(attributes, "username="+wumucConfig.Username)
+	}
+	return attributes
+}
+
+// This function will run the credential helper with the given action and attributes and return its output.
+func (store *HelperCredentialStore) invoke(action string, attributes []string) (string, error) {
+	arguments := strings.Fields(store.Command)
+	if len(arguments) == 0 {
+		return "", errors.New("credential helper command is empty")
+	}
+	logger.Debug(fmt.Sprintf("Invoking the credential helper: %s %s", store.Command, action))
+	command := exec.Command(arguments[0], append(arguments[1:], action)...)
+	command.Stdin = strings.NewReader(strings.Join(attributes, "\n") + "\n\n")
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return "", errors.New(fmt.Sprintf("credential helper '%s %s' failed. %v %s", store.Command, action, err,
+			strings.TrimSpace(stderr.String())))
+	}
+	return stdout.String(), nil
+}
+
+// This function will parse the key=value lines written by a credential helper. Parsing stops at the first empty line.
+func parseCredentialAttributes(output string) map[string]string {
+	attributes := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			break
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			attributes[parts[0]] = parts[1]
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+var testCredentials = Credentials{
+	AccessToken:   "test-access-token",
+	RefreshToken:  "test-refresh-token",
+	ExpiresIn:     3600,
+	TokenIssuedAt: 1500000000,
+}
+
+func TestFileCredentialStoreWithKeyFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	store := &FileCredentialStore{
+		FilePath:    filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_FILE),
+		KeyFilePath: filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_KEY_FILE),
+	}
+
+	if credentials, err := store.Get(&WUMUCConfig{}); err != nil || credentials != nil {
+		t.Errorf("Test failed, expected no credentials, found %v, error: %v", credentials, err)
+	}
+	if err = store.Store(&WUMUCConfig{}, &testCredentials); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(store.FilePath)
+	if err != nil || strings.Contains(string(data), testCredentials.RefreshToken) {
+		t.Errorf("Test failed, credentials are not encrypted: %s, error: %v", string(data), err)
+	}
+	if info, err := os.Stat(store.KeyFilePath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Test failed, key file not created with the expected permissions: %v, error: %v", info, err)
+	}
+	credentials, err := store.Get(&WUMUCConfig{})
+	if err != nil || *credentials != testCredentials {
+		t.Errorf("Test failed, unexpected credentials: %v, error: %v", credentials, err)
+	}
+
+	// Credentials cannot be decrypted with a different key
+	if err = ioutil.WriteFile(store.KeyFilePath, []byte(strings.Repeat("k", 32)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get(&WUMUCConfig{}); err == nil {
+		t.Error("Test failed, credentials decrypted with a different key")
+	}
+
+	if err = store.Erase(&WUMUCConfig{}); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+	if credentials, err = store.Get(&WUMUCConfig{}); err != nil || credentials != nil {
+		t.Errorf("Test failed, credentials not erased: %v, error: %v", credentials, err)
+	}
+}
+
+func TestFileCredentialStoreWithPassphrase(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	store := &FileCredentialStore{
+		FilePath:    filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_FILE),
+		KeyFilePath: filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_KEY_FILE),
+		Passphrase:  "passphrase",
+	}
+	if err = store.Store(&WUMUCConfig{}, &testCredentials); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if _, err = os.Stat(store.KeyFilePath); err == nil {
+		t.Error("Test failed, key file created when a passphrase is given")
+	}
+	credentials, err := store.Get(&WUMUCConfig{})
+	if err != nil || *credentials != testCredentials {
+		t.Errorf("Test failed, unexpected credentials: %v, error: %v", credentials, err)
+	}
+
+	for _, passphrase := range []string{"", "wrong"} {
+		store.Passphrase = passphrase
+		if _, err = store.Get(&WUMUCConfig{}); err == nil {
+			t.Errorf("Test failed, credentials decrypted with the passphrase '%s'", passphrase)
+		}
+	}
+}
+
+func TestHelperCredentialStore(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available:", err)
+	}
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	// This script is used as the credential helper, which keeps the attributes in a file
+	helperScript := filepath.Join(tempDir, "helper.sh")
+	storedFile := filepath.Join(tempDir, "stored")
+	script := "case \"$1\" in\n" +
+		"get) cat \"" + storedFile + "\" 2>/dev/null || true ;;\n" +
+		"store) cat > \"" + storedFile + "\" ;;\n" +
+		"erase) rm -f \"" + storedFile + "\" ;;\n" +
+		"*) exit 1 ;;\n" +
+		"esac\n"
+	if err = ioutil.WriteFile(helperScript, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := &HelperCredentialStore{Command: "sh " + helperScript}
+	config := &WUMUCConfig{Username: "user@wso2.com", TokenURL: "https://localhost:8243/token"}
+
+	if credentials, err := store.Get(config); err != nil || credentials != nil {
+		t.Errorf("Test failed, expected no credentials, found %v, error: %v", credentials, err)
+	}
+	if err = store.Store(config, &testCredentials); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	data, err := ioutil.ReadFile(storedFile)
+	if err != nil || !strings.Contains(string(data), "host=localhost:8243\n") ||
+		!strings.Contains(string(data), "username=user@wso2.com\n") {
+		t.Errorf("Test failed, unexpected attributes: %s, error: %v", string(data), err)
+	}
+	credentials, err := store.Get(config)
+	if err != nil || *credentials != testCredentials {
+		t.Errorf("Test failed, unexpected credentials: %v, error: %v", credentials, err)
+	}
+	if err = store.Erase(config); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+	if _, err = os.Stat(storedFile); err == nil {
+		t.Error("Test failed, credentials not erased")
+	}
+
+	store.Command = "sh " + filepath.Join(tempDir, "missing.sh")
+	if _, err = store.Get(config); err == nil {
+		t.Error("Test failed, expected an error from the failing credential helper")
+	}
+}
+
+func TestMigratePlainTextTokens(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer setEnv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "")()
+	previousConfig, previousConfigFilePath := wumucConfig, wumucConfigFilePath
+	defer func() { wumucConfig, wumucConfigFilePath = previousConfig, previousConfigFilePath }()
+
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	plainTextConfig := "serverurl: https://localhost\ntokenurl: https://localhost/token\n" +
+		"versionurl: https://localhost\nappkey: key\naccesstoken: plain-access-token\n" +
+		"refreshtoken: plain-refresh-token\n"
+	if err = ioutil.WriteFile(configFile, []byte(plainTextConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := LoadWUMUCConfig(tempDir)
+	if config.AccessToken != "plain-access-token" || config.RefreshToken != "plain-refresh-token" {
+		t.Errorf("Test failed, tokens not loaded: %v", config)
+	}
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil || strings.Contains(string(data), "plain-") || !strings.Contains(string(data), "appkey: key") {
+		t.Errorf("Test failed, tokens not removed from the config.yaml: %s, error: %v", string(data), err)
+	}
+	config = LoadWUMUCConfig(tempDir)
+	if config.AccessToken != "plain-access-token" || config.RefreshToken != "plain-refresh-token" {
+		t.Errorf("Test failed, tokens not loaded from the credential store: %v", config)
+	}
+}
+
+func TestReadConfigFileWithUnreadableCredentials(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer setEnv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "passphrase")()
+
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	if err = ioutil.WriteFile(configFile, []byte("serverurl: https://localhost\nappkey: key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, _, err := readConfigFile(configFile, "")
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	config.setCredentials(&testCredentials)
+	if err = WriteConfigFile(config, configFile); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+
+	// Credentials encrypted using the passphrase cannot be read without it, and should not be erased
+	os.Setenv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "")
+	if _, _, err = readConfigFile(configFile, ""); err == nil || !strings.Contains(err.Error(),
+		constant.WUMUC_CREDENTIALS_PASSPHRASE) {
+		t.Errorf("Test failed, expected an error about the passphrase, found: %v", err)
+	}
+	os.Setenv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "passphrase")
+	if config, _, err = readConfigFile(configFile, ""); err != nil || *config.getCredentials() != testCredentials {
+		t.Errorf("Test failed, unexpected credentials: %v, error: %v", config, err)
+	}
+}
+
+func TestLoadWUMUCConfigWithoutWUMUCHome(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	previousConfig, previousConfigFilePath := wumucConfig, wumucConfigFilePath
+	defer func() { wumucConfig, wumucConfigFilePath = previousConfig, previousConfigFilePath }()
+
+	// wum-uc home does not exist when wum-uc is run for the first time
+	wumucHome := filepath.Join(tempDir, "home")
+	LoadWUMUCConfig(wumucHome)
+	if exists, err := IsFileExists(filepath.Join(wumucHome, constant.WUMUC_CONFIG_FILE)); err != nil || !exists {
+		t.Errorf("Test failed, %s not created, error: %v", constant.WUMUC_CONFIG_FILE, err)
+	}
+}
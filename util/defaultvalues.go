@@ -14,6 +14,8 @@
 
 package util
 
+import "github.com/wso2/update-creator-tool/constant"
+
 // Default values used in the application
 var (
 	EnableDebugLogs = false
@@ -21,15 +23,61 @@ var (
 	// We only check md5 if -m flag is not found. If -m is set, it's value by default is true. That means we don't
 	// want to check md5 if this value is true. By default we want to check. So that's why we have set
 	// CheckMd5Disabled to false here.
-	CheckMd5Disabled        = false
+	CheckMd5Disabled = false
+	// Update zips are created in a reproducible manner by default
+	DeterministicZip = true
+	// Symbolic links are not allowed in the update directory by default
+	ResolveSymlinks         = false
 	ResourceFiles_Mandatory = []string{"LICENSE.txt"}
 	ResourceFiles_Optional  = []string{"update-descriptor.yaml", "update-descriptor3.yaml", "instructions.txt",
 		"NOT_A_CONTRIBUTION.txt"}
 	ResourceFiles_Skip = []string{"README.txt"}
-	PlatformVersions   = map[string]string{
-		"4.2.0": "turing",
-		"4.3.0": "perlis",
-		"4.4.0": "wilkes",
-		"5.0.0": "hamming",
+	// Platforms which updates can be created for. Deprecated platforms are not shown in the selection menu
+	Platforms = []Platform{
+		{Name: "turing", Version: "4.2.0", Deprecated: true},
+		{Name: "perlis", Version: "4.3.0", Deprecated: true},
+		{Name: "wilkes", Version: "4.4.0"},
+		{Name: "hamming", Version: "5.0.0"},
+	}
+	// Timeout, retries and backoff of the HTTP client. Failed requests are retried after 500ms, 1s, 2s...
+	HTTPClientTimeoutInSeconds             = 300
+	HTTPClientMaxRetries                   = 3
+	HTTPClientInitialBackoffInMilliseconds = 500
+	HTTPClientMaxBackoffInSeconds          = 30
+	// Hash algorithm used to generate checksums. MD5 checksums of old updates are still accepted
+	HashAlgorithm = "sha256"
+	// Maximum class file major version supported by each platform version. If the platform version is not found,
+	// the maximum version found in the distribution is used
+	MaxClassVersions = map[string]string{}
+	// Files with these extensions are considered as text files
+	TextFileExtensions = []string{".xml", ".properties", ".txt", ".yaml", ".yml", ".json", ".conf", ".cfg",
+		".ini", ".toml", ".sh", ".bat", ".jag", ".js", ".html", ".css", ".sql", ".xsd", ".wsdl", ".policy",
+		".xslt", ".xsl", ".md"}
+	// Files with these extensions are scanned for secrets in addition to the text files
+	SecretScanExtensions = []string{".pem", ".key"}
+	// Files with these extensions are considered as keystores
+	KeystoreExtensions = []string{".jks", ".jceks", ".p12", ".pfx", ".keystore"}
+	// Lint rules applied to the update-descriptor3.yaml and the resource files. These can be overridden in the
+	// config.yaml
+	LintRules = []LintRule{
+		{
+			Name:     "patch-word",
+			Type:     constant.LINT_RULE_FORBIDDEN,
+			Severity: constant.LINT_SEVERITY_WARNING,
+			Targets: []string{constant.UPDATE_DESCRIPTOR_V2_FILE, constant.UPDATE_DESCRIPTOR_V3_FILE,
+				constant.LICENSE_FILE, constant.INSTRUCTIONS_FILE, constant.NOT_A_CONTRIBUTION_FILE},
+			Pattern: constant.PATCH_REGEX,
+			Message: "contains the word 'patch' in following lines. Please review and change it to 'update' if " +
+				"possible.",
+		},
+		{
+			Name:      "restart-on-jar-change",
+			Type:      constant.LINT_RULE_REQUIRED,
+			Severity:  constant.LINT_SEVERITY_WARNING,
+			Targets:   []string{constant.LINT_TARGET_INSTRUCTIONS},
+			Pattern:   "(?i)restart",
+			Condition: constant.LINT_CONDITION_JARS_CHANGED,
+			Message:   "should mention that the server needs to be restarted as jars are changed by the update.",
+		},
 	}
 )
@@ -0,0 +1,305 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// struct which is used to make the development server fail the matching requests with the given status code. If the
+// count is 0, all the matching requests fail.
+type DevServerFault struct {
+	PathPrefix string
+	StatusCode int
+	Count      int
+}
+
+// Mock of the WUM servers which serves the token and revocation APIs, the applicable products API, the wum-uc version
+// check and the resource files from a fixtures directory. If a fixture is not found, a default response is generated.
+type DevServer struct {
+	FixturesDirectory string
+	faults            []*DevServerFault
+	lock              sync.Mutex
+}
+
+// This function will create a new development server which serves the fixtures in the given directory.
+func NewDevServer(fixturesDirectory string) *DevServer {
+	return &DevServer{FixturesDirectory: fixturesDirectory}
+}
+
+// This function will parse a fault given in the format path=status[:count] (ie. /token=401:1).
+func ParseDevServerFault(fault string) (*DevServerFault, error) {
+	parts := strings.SplitN(fault, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return nil, errors.New(fmt.Sprintf("invalid fault '%s'. It should be in the format path=status[:count]",
+			fault))
+	}
+	statusAndCount := strings.SplitN(parts[1], ":", 2)
+	statusCode, err := strconv.Atoi(statusAndCount[0])
+	if err != nil || http.StatusText(statusCode) == "" {
+		return nil, errors.New(fmt.Sprintf("invalid status code '%s' found in the fault '%s'", statusAndCount[0],
+			fault))
+	}
+	count := 0
+	if len(statusAndCount) == 2 {
+		if count, err = strconv.Atoi(statusAndCount[1]); err != nil || count < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid count '%s' found in the fault '%s'", statusAndCount[1],
+				fault))
+		}
+	}
+	return &DevServerFault{PathPrefix: "/" + strings.TrimPrefix(parts[0], "/"), StatusCode: statusCode,
+		Count: count}, nil
+}
+
+// This function will add the given fault to the server.
+func (server *DevServer) AddFault(fault *DevServerFault) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	server.faults = append(server.faults, fault)
+}
+
+// This function returns the fault which should be applied to the given request path, if any.
+func (server *DevServer) getFault(requestPath string) *DevServerFault {
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	for i, fault := range server.faults {
+		if !strings.HasPrefix(requestPath, fault.PathPrefix) {
+			continue
+		}
+		if fault.Count > 0 {
+			fault.Count--
+			if fault.Count == 0 {
+				server.faults = append(server.faults[:i], server.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// This function will handle the requests to the development server.
+func (server *DevServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger.Debug(fmt.Sprintf("[dev-server] %s %s", r.Method, r.URL.Path))
+	if r.URL.Path == constant.DEV_SERVER_FAULTS_CONTEXT {
+		server.handleFaultRequest(w, r)
+		return
+	}
+	if fault := server.getFault(r.URL.Path); fault != nil {
+		logger.Debug(fmt.Sprintf("[dev-server] Failing %s with %d", r.URL.Path, fault.StatusCode))
+		writeDevServerError(w, fault.StatusCode)
+		return
+	}
+	switch {
+	case r.URL.Path == "/"+constant.TOKEN_API_CONTEXT && r.Method == http.MethodPost:
+		server.handleTokenRequest(w, r)
+	case r.URL.Path == "/"+constant.REVOKE_API_CONTEXT && r.Method == http.MethodPost:
+		handleRevokeRequest(w, r)
+	case r.URL.Path == "/"+constant.FILES_API_CONTEXT+"/"+constant.FILES_API_VERSION+"/"+
+		constant.APPLICABLE_PRODUCTS && r.Method == http.MethodPost:
+		server.handleApplicableProductsRequest(w, r)
+	case strings.HasPrefix(r.URL.Path, "/"+constant.WUMUCADMIN_API_CONTEXT+"/"+constant.VERSION+"/"):
+		server.writeFixture(w, constant.DEV_SERVER_VERSION_FIXTURE, VersionResponse{IsCompatible: true})
+	case strings.HasPrefix(r.URL.Path, constant.DEV_SERVER_LICENSE_CONTEXT):
+		server.handleResourceFileRequest(w, strings.TrimPrefix(r.URL.Path, constant.DEV_SERVER_LICENSE_CONTEXT))
+	default:
+		writeDevServerError(w, http.StatusNotFound)
+	}
+}
+
+// This function will add a fault given in the 'fault' query parameter, or remove all the faults if the method is
+// DELETE.
+func (server *DevServer) handleFaultRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		server.lock.Lock()
+		server.faults = nil
+		server.lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	fault, err := ParseDevServerFault(r.URL.Query().Get("fault"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	server.AddFault(fault)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// This function will issue tokens for the password, refresh token and client credentials grants. Only the password or
+// the client secret in the DEV_SERVER_INVALID_PASSWORD constant is rejected, so that the invalid credentials flow can
+// be tested.
+func (server *DevServer) handleTokenRequest(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeDevServerError(w, http.StatusBadRequest)
+		return
+	}
+	switch r.PostForm.Get("grant_type") {
+	case "password":
+		if r.PostForm.Get("password") == constant.DEV_SERVER_INVALID_PASSWORD {
+			writeDevServerJSON(w, http.StatusBadRequest, TokenErrResp{Error: constant.INVALID_GRANT,
+				ErrorDescription: "invalid credentials"})
+			return
+		}
+	case "refresh_token":
+		if len(r.PostForm.Get("refresh_token")) == 0 {
+			writeDevServerJSON(w, http.StatusBadRequest, TokenErrResp{Error: constant.INVALID_GRANT,
+				ErrorDescription: "invalid refresh token"})
+			return
+		}
+	case "client_credentials":
+		// Refresh tokens are not issued for the client credentials grant
+		if _, clientSecret, ok := r.BasicAuth(); !ok || clientSecret == constant.DEV_SERVER_INVALID_PASSWORD {
+			writeDevServerJSON(w, http.StatusUnauthorized, TokenErrResp{Error: "invalid_client",
+				ErrorDescription: "invalid client credentials"})
+			return
+		}
+		server.writeFixture(w, constant.DEV_SERVER_TOKEN_FIXTURE, TokenResponse{
+			TokenType:   "bearer",
+			ExpiresIn:   3600,
+			AccessToken: constant.DEV_SERVER_ACCESS_TOKEN,
+		})
+		return
+	default:
+		writeDevServerJSON(w, http.StatusBadRequest, TokenErrResp{Error: "unsupported_grant_type"})
+		return
+	}
+	server.writeFixture(w, constant.DEV_SERVER_TOKEN_FIXTURE, TokenResponse{
+		TokenType:    "bearer",
+		ExpiresIn:    3600,
+		AccessToken:  constant.DEV_SERVER_ACCESS_TOKEN,
+		RefreshToken: constant.DEV_SERVER_REFRESH_TOKEN,
+	})
+}
+
+// This function will accept the revocation of any non empty token.
+func handleRevokeRequest(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || len(r.PostForm.Get("token")) == 0 {
+		writeDevServerError(w, http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// This function will find the products affected by the files in the request. If the fixture is not found, products
+// are computed using the product catalog in the fixtures directory, or else all the files are reported as applicable
+// to a single product.
+func (server *DevServer) handleApplicableProductsRequest(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get(constant.HEADER_AUTHORIZATION), "Bearer ") {
+		writeDevServerError(w, http.StatusUnauthorized)
+		return
+	}
+	request := PartialUpdateFileRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeDevServerError(w, http.StatusBadRequest)
+		return
+	}
+	catalogPath := filepath.Join(server.FixturesDirectory, constant.PRODUCT_CATALOG_DIRECTORY, request.PlatformName)
+	var response *PartialUpdatedFileResponse
+	if products, err := LoadCatalogProducts(catalogPath); err == nil && len(products) > 0 {
+		response = ComputePartialUpdatedFiles(&request, products)
+	} else {
+		response = ComputePartialUpdatedFiles(&request, []*CatalogProduct{})
+		response.BackwardCompatible = true
+		response.CompatibleProducts = append(response.CompatibleProducts, PartialUpdatedProducts{
+			ProductName:   constant.DEV_SERVER_PRODUCT_NAME,
+			BaseVersion:   constant.DEV_SERVER_PRODUCT_VERSION,
+			AddedFiles:    append([]string{}, request.AddedFiles...),
+			ModifiedFiles: append([]string{}, request.ModifiedFiles...),
+			RemovedFiles:  append([]string{}, request.RemovedFiles...),
+		})
+	}
+	server.writeFixture(w, constant.DEV_SERVER_APPLICABLE_PRODUCTS_FIXTURE, response)
+}
+
+// This function will serve the given resource file (ie. LICENSE.txt) from the fixtures directory. Checksums of the
+// resource files are generated if they are not found in the fixtures directory.
+func (server *DevServer) handleResourceFileRequest(w http.ResponseWriter, fileName string) {
+	fileName = filepath.Base(fileName)
+	data, err := ioutil.ReadFile(filepath.Join(server.FixturesDirectory, fileName))
+	if err == nil {
+		w.Write(data)
+		return
+	}
+	for _, algorithm := range []string{constant.HASH_ALGORITHM_SHA256, constant.HASH_ALGORITHM_MD5} {
+		extension := "." + algorithm
+		if !strings.HasSuffix(fileName, extension) {
+			continue
+		}
+		data, err = ioutil.ReadFile(filepath.Join(server.FixturesDirectory, strings.TrimSuffix(fileName, extension)))
+		if err != nil {
+			break
+		}
+		checksum, err := GetChecksumOfData(data, algorithm)
+		if err != nil {
+			writeDevServerError(w, http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(checksum + "\n"))
+		return
+	}
+	writeDevServerError(w, http.StatusNotFound)
+}
+
+// This function returns the SHA-256 checksum of the given resource file in the fixtures directory.
+func (server *DevServer) GetResourceFileChecksum(fileName string) (string, error) {
+	return GetChecksum(filepath.Join(server.FixturesDirectory, fileName), constant.HASH_ALGORITHM_SHA256)
+}
+
+// This function will write the given fixture file if it exists in the fixtures directory, or else the given default
+// response as json.
+func (server *DevServer) writeFixture(w http.ResponseWriter, fixtureName string, defaultResponse interface{}) {
+	data, err := ioutil.ReadFile(filepath.Join(server.FixturesDirectory, fixtureName))
+	if err == nil {
+		w.Header().Set(constant.HEADER_CONTENT_TYPE, constant.HEADER_VALUE_APPLICATION_JSON)
+		w.Write(data)
+		return
+	}
+	if !os.IsNotExist(err) {
+		logger.Error(fmt.Sprintf("[dev-server] Error occurred while reading the fixture '%s': %v", fixtureName, err))
+	}
+	writeDevServerJSON(w, http.StatusOK, defaultResponse)
+}
+
+// This function will write an error response with the given status code. Retry-After header is added to the 429
+// responses.
+func writeDevServerError(w http.ResponseWriter, statusCode int) {
+	if statusCode == http.StatusTooManyRequests {
+		w.Header().Set(constant.HEADER_RETRY_AFTER, "1")
+	}
+	errorResponse := ErrorResponse{}
+	errorResponse.Error.Code = statusCode
+	errorResponse.Error.Message = http.StatusText(statusCode)
+	writeDevServerJSON(w, statusCode, errorResponse)
+}
+
+// This function will write the given response as json with the given status code.
+func writeDevServerJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set(constant.HEADER_CONTENT_TYPE, constant.HEADER_VALUE_APPLICATION_JSON)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error(fmt.Sprintf("[dev-server] Error occurred while writing the response: %v", err))
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function will start the development server with a fixtures directory which contains the LICENSE.txt.
+func startDevServer(t *testing.T) (*DevServer, *httptest.Server, func()) {
+	fixturesDirectory, err := ioutil.TempDir("", "wum-uc-fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(fixturesDirectory, constant.LICENSE_FILE), []byte("abc"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devServer := NewDevServer(fixturesDirectory)
+	server := httptest.NewServer(devServer)
+	return devServer, server, func() {
+		server.Close()
+		os.RemoveAll(fixturesDirectory)
+	}
+}
+
+func TestDevServerTokenAPI(t *testing.T) {
+	_, server, cleanup := startDevServer(t)
+	defer cleanup()
+	config := WUMUCConfig{TokenURL: server.URL + "/" + constant.TOKEN_API_CONTEXT}
+
+	tokenResponse, err := GetAccessToken("user@wso2.com", []byte("password"), &config, "")
+	if err != nil || tokenResponse.AccessToken != constant.DEV_SERVER_ACCESS_TOKEN {
+		t.Errorf("Test failed, unexpected token response: %v, error: %v", tokenResponse, err)
+	}
+	if _, err = GetAccessToken("user@wso2.com", []byte(constant.DEV_SERVER_INVALID_PASSWORD), &config,
+		""); err == nil {
+		t.Error("Test failed, invalid credentials accepted")
+	}
+	config.RefreshToken = tokenResponse.RefreshToken
+	if tokenResponse, err = RenewAccessToken(&config); err != nil ||
+		tokenResponse.RefreshToken != constant.DEV_SERVER_REFRESH_TOKEN {
+		t.Errorf("Test failed, unexpected token response: %v, error: %v", tokenResponse, err)
+	}
+}
+
+func TestDevServerApplicableProducts(t *testing.T) {
+	_, server, cleanup := startDevServer(t)
+	defer cleanup()
+	previousConfig := wumucConfig
+	defer func() { wumucConfig = previousConfig }()
+	wumucConfig = WUMUCConfig{ServerURL: server.URL, AccessToken: constant.DEV_SERVER_ACCESS_TOKEN}
+
+	updateDescriptor := UpdateDescriptorV2{UpdateNumber: "0001", PlatformName: "wilkes", PlatformVersion: "4.4.0"}
+	updateDescriptor.FileChanges.ModifiedFiles = []string{"bin/wso2server.sh"}
+	response, err := GetPartialUpdatedFiles(&updateDescriptor)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if response.UpdateNumber != "0001" || len(response.CompatibleProducts) != 1 ||
+		response.CompatibleProducts[0].ProductName != constant.DEV_SERVER_PRODUCT_NAME ||
+		len(response.CompatibleProducts[0].ModifiedFiles) != 1 {
+		t.Errorf("Test failed, unexpected response: %v", response)
+	}
+}
+
+func TestDevServerFaults(t *testing.T) {
+	devServer, server, cleanup := startDevServer(t)
+	defer cleanup()
+	tokenURL := server.URL + "/" + constant.TOKEN_API_CONTEXT
+	payload := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"token"}}
+
+	fault, err := ParseDevServerFault("token=429:1")
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	devServer.AddFault(fault)
+	response, err := http.PostForm(tokenURL, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusTooManyRequests || response.Header.Get(constant.HEADER_RETRY_AFTER) == "" {
+		t.Errorf("Test failed, expected 429 with Retry-After, found %d", response.StatusCode)
+	}
+	if response, err = http.PostForm(tokenURL, payload); err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Test failed, fault applied more than once: %d", response.StatusCode)
+	}
+
+	// Faults can be added while the server is running
+	response, err = http.Post(server.URL+constant.DEV_SERVER_FAULTS_CONTEXT+"?fault=/token=500", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	for i := 0; i < 2; i++ {
+		if response, err = http.PostForm(tokenURL, payload); err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Test failed, expected 500, found %d", response.StatusCode)
+		}
+	}
+
+	for _, invalidFault := range []string{"token", "token=abc", "token=999", "token=500:-1"} {
+		if _, err = ParseDevServerFault(invalidFault); err == nil {
+			t.Errorf("Test failed, invalid fault '%s' accepted", invalidFault)
+		}
+	}
+}
+
+func TestDevServerResourceFiles(t *testing.T) {
+	devServer, server, cleanup := startDevServer(t)
+	defer cleanup()
+	licenseURL := server.URL + constant.DEV_SERVER_LICENSE_CONTEXT + constant.LICENSE_FILE
+
+	data, err := GetContentFromUrl(licenseURL)
+	if err != nil || string(data) != "abc" {
+		t.Errorf("Test failed, unexpected content: %s, error: %v", string(data), err)
+	}
+	data, err = GetContentFromUrl(licenseURL + "." + constant.HASH_ALGORITHM_SHA256)
+	expected := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if err != nil || strings.TrimSpace(string(data)) != expected {
+		t.Errorf("Test failed, unexpected checksum: %s, error: %v", string(data), err)
+	}
+	if checksum, err := devServer.GetResourceFileChecksum(constant.LICENSE_FILE); err != nil || checksum != expected {
+		t.Errorf("Test failed, unexpected checksum: %s, error: %v", checksum, err)
+	}
+	if _, err = GetContentFromUrl(server.URL + constant.DEV_SERVER_LICENSE_CONTEXT + "missing.txt"); err == nil {
+		t.Error("Test failed, missing file served")
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Maximum number of cells in the LCS table (ie. about 16 MB). Files with larger changed regions are not diffed line by
+// line to avoid high memory usage.
+const maxDiffTableSize = 4000000
+
+// This struct is used to store a single operation of an edit script.
+type diffOperation struct {
+	// One of ' ', '-' or '+'
+	kind      byte
+	fromIndex int
+	toIndex   int
+	line      string
+}
+
+// This function checks whether the given file is a text file which can be diffed, using the file extension.
+func IsTextFile(fileName string) bool {
+	extension := strings.ToLower(path.Ext(fileName))
+	return IsStringIsInSlice(extension, TextFileExtensions)
+}
+
+// This function will split the given content to lines. Trailing new line of the content is ignored.
+func SplitLines(content string) []string {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	if len(content) == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// This function will return the unified diff of the given lines with the given number of context lines. An empty
+// string is returned if there are no differences.
+func UnifiedDiff(fromName, toName string, fromLines, toLines []string, contextLines int) string {
+	operations, computed := computeEditScript(fromLines, toLines)
+	if !computed {
+		return fmt.Sprintf("Files %s and %s differ\n", fromName, toName)
+	}
+
+	// Find the indices of all changes
+	changes := make([]int, 0)
+	for i, operation := range operations {
+		if operation.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+	// Group changes which are close to each other to a single hunk
+	hunkStart := 0
+	for i := 1; i <= len(changes); i++ {
+		if i < len(changes) && changes[i]-changes[i-1] <= 2*contextLines+1 {
+			continue
+		}
+		start := changes[hunkStart] - contextLines
+		if start < 0 {
+			start = 0
+		}
+		end := changes[i-1] + contextLines + 1
+		if end > len(operations) {
+			end = len(operations)
+		}
+		writeHunk(&buffer, operations[start:end])
+		hunkStart = i
+	}
+	return buffer.String()
+}
+
+// This function will write the given operations as a single hunk to the buffer.
+func writeHunk(buffer *bytes.Buffer, operations []diffOperation) {
+	fromCount, toCount := 0, 0
+	for _, operation := range operations {
+		if operation.kind != '+' {
+			fromCount++
+		}
+		if operation.kind != '-' {
+			toCount++
+		}
+	}
+	// Line numbers are 1 based. If a hunk has no lines from a file, the line number is the line before the hunk.
+	fromLine := operations[0].fromIndex + 1
+	if fromCount == 0 {
+		fromLine = operations[0].fromIndex
+	}
+	toLine := operations[0].toIndex + 1
+	if toCount == 0 {
+		toLine = operations[0].toIndex
+	}
+	buffer.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount))
+	for _, operation := range operations {
+		buffer.WriteByte(operation.kind)
+		buffer.WriteString(operation.line)
+		buffer.WriteString("\n")
+	}
+}
+
+// This function will compute the edit script to convert fromLines to toLines using the longest common subsequence.
+// Common lines at the beginning and the end are excluded from the LCS table, so only the size of the changed region
+// is limited. False is returned if the changed region is too large to be diffed.
+func computeEditScript(fromLines, toLines []string) ([]diffOperation, bool) {
+	n, m := len(fromLines), len(toLines)
+	prefix := 0
+	for prefix < n && prefix < m && fromLines[prefix] == toLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && fromLines[n-1-suffix] == toLines[m-1-suffix] {
+		suffix++
+	}
+	rows, columns := n-prefix-suffix+1, m-prefix-suffix+1
+	if rows*columns > maxDiffTableSize {
+		return nil, false
+	}
+
+	// lcs[i*columns+j] is the length of the longest common subsequence of the changed regions starting from
+	// fromLines[prefix+i] and toLines[prefix+j]
+	lcs := make([]int32, rows*columns)
+	for i := rows - 2; i >= 0; i-- {
+		for j := columns - 2; j >= 0; j-- {
+			if fromLines[prefix+i] == toLines[prefix+j] {
+				lcs[i*columns+j] = lcs[(i+1)*columns+j+1] + 1
+			} else if lcs[(i+1)*columns+j] >= lcs[i*columns+j+1] {
+				lcs[i*columns+j] = lcs[(i+1)*columns+j]
+			} else {
+				lcs[i*columns+j] = lcs[i*columns+j+1]
+			}
+		}
+	}
+
+	operations := make([]diffOperation, 0, n+m)
+	for i := 0; i < prefix; i++ {
+		operations = append(operations, diffOperation{' ', i, i, fromLines[i]})
+	}
+	i, j := 0, 0
+	for i < rows-1 && j < columns-1 {
+		if fromLines[prefix+i] == toLines[prefix+j] {
+			operations = append(operations, diffOperation{' ', prefix + i, prefix + j, fromLines[prefix+i]})
+			i++
+			j++
+		} else if lcs[(i+1)*columns+j] >= lcs[i*columns+j+1] {
+			operations = append(operations, diffOperation{'-', prefix + i, prefix + j, fromLines[prefix+i]})
+			i++
+		} else {
+			operations = append(operations, diffOperation{'+', prefix + i, prefix + j, toLines[prefix+j]})
+			j++
+		}
+	}
+	for ; i < rows-1; i++ {
+		operations = append(operations, diffOperation{'-', prefix + i, prefix + j, fromLines[prefix+i]})
+	}
+	for ; j < columns-1; j++ {
+		operations = append(operations, diffOperation{'+', prefix + i, prefix + j, toLines[prefix+j]})
+	}
+	for k := 0; k < suffix; k++ {
+		operations = append(operations, diffOperation{' ', n - suffix + k, m - suffix + k, fromLines[n-suffix+k]})
+	}
+	return operations, true
+}
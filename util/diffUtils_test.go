@@ -0,0 +1,97 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	from := SplitLines("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n")
+	to := SplitLines("a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+	expected := `--- old
++++ new
+@@ -1,7 +1,7 @@
+ a
+ b
+ c
+-d
++D
+ e
+ f
+ g
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`
+	actual := UnifiedDiff("old", "new", from, to, 3)
+	if actual != expected {
+		t.Errorf("Test failed, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestUnifiedDiffWithoutChanges(t *testing.T) {
+	lines := SplitLines("a\nb\n")
+	actual := UnifiedDiff("old", "new", lines, lines, 3)
+	if actual != "" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "", actual)
+	}
+}
+
+func TestUnifiedDiffOfEmptyFile(t *testing.T) {
+	expected := "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n"
+	actual := UnifiedDiff("old", "new", SplitLines(""), SplitLines("a\n"), 3)
+	if actual != expected {
+		t.Errorf("Test failed, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+func TestUnifiedDiffOfLargeFile(t *testing.T) {
+	from := make([]string, 0)
+	to := make([]string, 0)
+	for i := 0; i < 5000; i++ {
+		from = append(from, fmt.Sprintf("line %d", i))
+		to = append(to, fmt.Sprintf("line %d", i))
+	}
+	to[2500] = "changed"
+
+	// Only the changed region should be diffed using the LCS table
+	actual := UnifiedDiff("old", "new", from, to, 1)
+	expected := "--- old\n+++ new\n@@ -2500,3 +2500,3 @@\n line 2499\n-line 2500\n+changed\n line 2501\n"
+	if actual != expected {
+		t.Errorf("Test failed, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	for i := range to {
+		to[i] = strings.ToUpper(to[i])
+	}
+	expected = "Files old and new differ\n"
+	if actual = UnifiedDiff("old", "new", from, to, 1); actual != expected {
+		t.Errorf("Test failed, expected: %s, actual: %s", expected, actual)
+	}
+}
+
+func TestIsTextFile(t *testing.T) {
+	if !IsTextFile("repository/conf/carbon.XML") {
+		t.Errorf("Test failed, carbon.XML should be a text file")
+	}
+	if IsTextFile("repository/components/plugins/foo.jar") {
+		t.Errorf("Test failed, foo.jar should not be a text file")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function returns the editor command configured using the VISUAL or EDITOR environment variables. An empty
+// slice is returned if an editor is not configured.
+func GetEditorCommand() []string {
+	for _, envName := range []string{constant.VISUAL, constant.EDITOR} {
+		if command := strings.Fields(os.Getenv(envName)); len(command) > 0 {
+			return command
+		}
+	}
+	return []string{}
+}
+
+// This function will open the given content in the configured editor and return the edited content. The given
+// instructions are added as comments to the top of the file. Comment lines and leading and trailing spaces are removed
+// from the edited content.
+func EditInEditor(content, instructions string) (string, error) {
+	command := GetEditorCommand()
+	if len(command) == 0 {
+		return "", errors.New(fmt.Sprintf("editor is not configured. Set the %s or %s environment variable",
+			constant.VISUAL, constant.EDITOR))
+	}
+	file, err := ioutil.TempFile("", "wum-uc-edit-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	header := ""
+	for _, line := range SplitLines(instructions) {
+		header += constant.EDITOR_COMMENT_PREFIX + " " + line + "\n"
+	}
+	_, err = file.WriteString(header + content)
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debug(fmt.Sprintf("Opening %s using %v", file.Name(), command))
+	editorCommand := exec.Command(command[0], append(command[1:], file.Name())...)
+	editorCommand.Stdin = os.Stdin
+	editorCommand.Stdout = os.Stdout
+	editorCommand.Stderr = os.Stderr
+	if err = editorCommand.Run(); err != nil {
+		return "", errors.New(fmt.Sprintf("error occurred while running the editor '%s'. %v",
+			strings.Join(command, " "), err))
+	}
+	data, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return removeCommentLines(string(data)), nil
+}
+
+// This function will remove the comment lines and leading and trailing spaces of the given content.
+func removeCommentLines(content string) string {
+	lines := make([]string, 0)
+	for _, line := range SplitLines(content) {
+		if strings.HasPrefix(line, constant.EDITOR_COMMENT_PREFIX) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// This function will read multiple lines from the user until an empty line is entered.
+func GetMultiLineUserInput() (string, error) {
+	return readLinesUntilEmptyLine(os.Stdin)
+}
+
+// This function will read lines from the given reader until an empty line or the end is reached.
+func readLinesUntilEmptyLine(input io.Reader) (string, error) {
+	scanner := bufio.NewScanner(input)
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if len(line) == 0 {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func setEnv(name, value string) func() {
+	oldValue, exists := os.LookupEnv(name)
+	os.Setenv(name, value)
+	return func() {
+		if exists {
+			os.Setenv(name, oldValue)
+		} else {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestEditInEditor(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available:", err)
+	}
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	// This script is used as the editor, which replaces 'old' with 'new' in the file
+	editorScript := filepath.Join(tempDir, "editor.sh")
+	script := "sed 's/old/new/' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+	if err = ioutil.WriteFile(editorScript, []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer setEnv(constant.VISUAL, "")()
+	defer setEnv(constant.EDITOR, "sh "+editorScript)()
+
+	edited, err := EditInEditor("\nold description\nsecond line\n\n", "Enter the description.\nSecond line")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "new description\nsecond line"
+	if edited != expected {
+		t.Errorf("Test failed, expected: %q, actual: %q", expected, edited)
+	}
+}
+
+func TestEditInEditorNotConfigured(t *testing.T) {
+	defer setEnv(constant.VISUAL, "")()
+	defer setEnv(constant.EDITOR, "")()
+	if _, err := EditInEditor("content", "instructions"); err == nil {
+		t.Errorf("Test failed, expected an error as the editor is not configured")
+	}
+}
+
+func TestReadLinesUntilEmptyLine(t *testing.T) {
+	input := "first line\r\n  indented line  \n\nnot read\n"
+	lines, err := readLinesUntilEmptyLine(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "first line\n  indented line"
+	if lines != expected {
+		t.Errorf("Test failed, expected: %q, actual: %q", expected, lines)
+	}
+}
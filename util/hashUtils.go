@@ -0,0 +1,83 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function returns the hash algorithm configured for generating checksums. If the configured algorithm is not
+// supported, the default algorithm is returned.
+func GetHashAlgorithm() string {
+	algorithm := strings.ToLower(viper.GetString(constant.HASH_ALGORITHM))
+	if _, err := NewHash(algorithm); err != nil {
+		logger.Debug(fmt.Sprintf("Hash algorithm '%s' is not supported, using '%s'.", algorithm,
+			HashAlgorithm))
+		return HashAlgorithm
+	}
+	return algorithm
+}
+
+// This function returns a new hash of the given algorithm.
+func NewHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case constant.HASH_ALGORITHM_MD5:
+		return md5.New(), nil
+	case constant.HASH_ALGORITHM_SHA256:
+		return sha256.New(), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported hash algorithm '%s'. Supported algorithms are '%s' and "+
+			"'%s'", algorithm, constant.HASH_ALGORITHM_SHA256, constant.HASH_ALGORITHM_MD5))
+	}
+}
+
+// This function returns the checksum of the file in the given filepath using the given hash algorithm.
+func GetChecksum(filepath, algorithm string) (string, error) {
+	hash, err := NewHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// This function returns the checksum of the given data using the given hash algorithm.
+func GetChecksumOfData(data []byte, algorithm string) (string, error) {
+	hash, err := NewHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
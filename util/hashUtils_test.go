@@ -0,0 +1,73 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestGetChecksumOfData(t *testing.T) {
+	checksums := map[string]string{
+		constant.HASH_ALGORITHM_MD5:    "900150983cd24fb0d6963f7d28e17f72",
+		constant.HASH_ALGORITHM_SHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+	}
+	for algorithm, expected := range checksums {
+		actual, err := GetChecksumOfData([]byte("abc"), algorithm)
+		if err != nil {
+			t.Fatalf("Test failed, unexpected error: %v", err)
+		}
+		if actual != expected {
+			t.Errorf("Test failed, expected: %s, actual: %s", expected, actual)
+		}
+	}
+	if _, err := GetChecksumOfData([]byte("abc"), "sha1"); err == nil {
+		t.Errorf("Test failed, error expected for unsupported algorithm")
+	}
+}
+
+func TestGetHashAlgorithm(t *testing.T) {
+	defer viper.Set(constant.HASH_ALGORITHM, nil)
+
+	viper.Set(constant.HASH_ALGORITHM, "MD5")
+	if algorithm := GetHashAlgorithm(); algorithm != constant.HASH_ALGORITHM_MD5 {
+		t.Errorf("Test failed, expected: %s, actual: %s", constant.HASH_ALGORITHM_MD5, algorithm)
+	}
+	viper.Set(constant.HASH_ALGORITHM, "unknown")
+	if algorithm := GetHashAlgorithm(); algorithm != constant.HASH_ALGORITHM_SHA256 {
+		t.Errorf("Test failed, expected: %s, actual: %s", constant.HASH_ALGORITHM_SHA256, algorithm)
+	}
+}
+
+func TestGenerateChecksumForGeneratedContent(t *testing.T) {
+	updateDescriptorV3 := UpdateDescriptorV3{
+		UpdateNumber:    "1234",
+		PlatformVersion: "4.4.0",
+		PlatformName:    "wilkes",
+		CompatibleProducts: []ProductChanges{
+			{ProductName: "wso2am", ProductVersion: "2.1.0", AddedFiles: []string{"a.jar"}},
+		},
+	}
+	md5sum := GenerateChecksumForGeneratedContent(&updateDescriptorV3, constant.HASH_ALGORITHM_MD5)
+	if len(md5sum) != 32 {
+		t.Errorf("Test failed, expected an md5 checksum, actual: %s", md5sum)
+	}
+	sha256sum := GenerateChecksumForGeneratedContent(&updateDescriptorV3, constant.HASH_ALGORITHM_SHA256)
+	if len(sha256sum) != 64 {
+		t.Errorf("Test failed, expected a sha256 checksum, actual: %s", sha256sum)
+	}
+}
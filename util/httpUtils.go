@@ -0,0 +1,284 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// Error returned when the server responds with an unsuccessful status code.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	// Message found in the error response, if any
+	Message string
+}
+
+// This function returns a human readable description of the error.
+func (err *HTTPError) Error() string {
+	if len(err.Message) > 0 {
+		return fmt.Sprintf("request to '%s' failed with the status '%s'. %s", err.URL, err.Status, err.Message)
+	}
+	return fmt.Sprintf("request to '%s' failed with the status '%s'", err.URL, err.Status)
+}
+
+// HTTP client which is shared by all the requests sent by wum-uc. Requests which fail due to connection errors or
+// with 429 and 5xx status codes are retried with an exponential backoff. Retry-After header of the response is
+// honoured, and the request is not retried if the server asks to wait longer than the maximum backoff.
+type HTTPClient struct {
+	Client         *http.Client
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	sleep          func(time.Duration)
+}
+
+var httpClient *HTTPClient
+
+// This function returns the HTTP client created using the HTTP_CLIENT configurations in the config.yaml.
+func GetHTTPClient() *HTTPClient {
+	if httpClient == nil {
+		client, err := NewHTTPClient()
+		if err != nil {
+			HandleErrorAndExit(err, "Error occurred while creating the HTTP client.")
+		}
+		httpClient = client
+	}
+	return httpClient
+}
+
+// This function will create a new HTTP client using the HTTP_CLIENT configurations in the config.yaml. If a proxy is
+// not configured, the proxy is read from the HTTP_PROXY and HTTPS_PROXY environment variables. Certificates in the
+// configured CA bundle are trusted in addition to the system certificates.
+func NewHTTPClient() (*HTTPClient, error) {
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if proxy := viper.GetString(constant.HTTP_CLIENT_PROXY); len(proxy) > 0 {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid %s '%s'. %v", constant.HTTP_CLIENT_PROXY, proxy, err))
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if caBundle := viper.GetString(constant.HTTP_CLIENT_CA_BUNDLE); len(caBundle) > 0 {
+		rootCAs, err := loadCABundle(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+	initialBackoff := viper.GetInt(constant.HTTP_CLIENT_INITIAL_BACKOFF_IN_MILLISECONDS)
+	return &HTTPClient{
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(viper.GetInt(constant.HTTP_CLIENT_TIMEOUT_IN_SECONDS)) * time.Second,
+		},
+		MaxRetries:     viper.GetInt(constant.HTTP_CLIENT_MAX_RETRIES),
+		InitialBackoff: time.Duration(initialBackoff) * time.Millisecond,
+		MaxBackoff:     time.Duration(viper.GetInt(constant.HTTP_CLIENT_MAX_BACKOFF_IN_SECONDS)) * time.Second,
+	}, nil
+}
+
+// This function will read the PEM encoded certificates in the given CA bundle and add them to the system certificates.
+func loadCABundle(location string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("error occurred while reading the CA bundle '%s'. %v", location, err))
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(data) {
+		return nil, errors.New(fmt.Sprintf("no certificates found in the CA bundle '%s'", location))
+	}
+	return rootCAs, nil
+}
+
+// This function returns a copy of the client which does not retry failed requests. It should be used for the requests
+// which are not idempotent.
+func (client *HTTPClient) WithoutRetries() *HTTPClient {
+	clientCopy := *client
+	clientCopy.MaxRetries = 0
+	return &clientCopy
+}
+
+// This function will send the given request. Requests which fail due to connection errors or with 429 and 5xx status
+// codes are retried. If the retries are exhausted or the server asks to wait longer than the maximum backoff, an
+// HTTPError is returned for the 429 and 5xx responses. Other responses are returned to the caller.
+func (client *HTTPClient) Do(request *http.Request) (*http.Response, error) {
+	// Body of the request is buffered, so that it can be sent again when retrying
+	if request.Body != nil && request.GetBody == nil {
+		body, err := ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		request.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			request.Body = body
+		}
+		response, err := client.Client.Do(request)
+		if err == nil && !isRetryableStatus(response.StatusCode) {
+			return response, nil
+		}
+		if err == nil {
+			err = newHTTPError(request.URL.String(), response)
+		}
+		if attempt >= client.MaxRetries {
+			return nil, err
+		}
+		backoff, canRetry := client.getBackoff(attempt, response)
+		if !canRetry {
+			logger.Debug(fmt.Sprintf("Request to %s failed: %v. Retry-After of the response exceeds the maximum "+
+				"backoff %v", request.URL, err, client.MaxBackoff))
+			return nil, err
+		}
+		logger.Debug(fmt.Sprintf("Request to %s failed: %v. Retrying in %v", request.URL, err, backoff))
+		client.sleepFor(backoff)
+	}
+}
+
+// This function will send a GET request to the given URL and return the response body. An HTTPError is returned if
+// the response is not successful.
+func (client *HTTPClient) Get(url string) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, newHTTPError(url, response)
+	}
+	defer response.Body.Close()
+	return ioutil.ReadAll(response.Body)
+}
+
+// This function will download the content of the given URL to the given file.
+func (client *HTTPClient) Download(url, file string) error {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return newHTTPError(url, response)
+	}
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, response.Body)
+	return err
+}
+
+// This function returns the time to wait before the given retry attempt. Retry-After header of the response takes
+// precedence over the exponential backoff, which is limited by the maximum backoff. False is returned if the
+// Retry-After exceeds the maximum backoff, as the request should not be retried in that case.
+func (client *HTTPClient) getBackoff(attempt int, response *http.Response) (time.Duration, bool) {
+	if response != nil {
+		if retryAfter, found := parseRetryAfter(response.Header.Get(constant.HEADER_RETRY_AFTER)); found {
+			if retryAfter > client.MaxBackoff {
+				return 0, false
+			}
+			if retryAfter < 0 {
+				retryAfter = 0
+			}
+			return retryAfter, true
+		}
+	}
+	backoff := client.InitialBackoff << uint(attempt)
+	if backoff > client.MaxBackoff || backoff < 0 {
+		backoff = client.MaxBackoff
+	}
+	return backoff, true
+}
+
+// This function will wait for the given duration.
+func (client *HTTPClient) sleepFor(duration time.Duration) {
+	if client.sleep != nil {
+		client.sleep(duration)
+		return
+	}
+	time.Sleep(duration)
+}
+
+// This function will parse the value of the Retry-After header which can either be a number of seconds or a date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// This function checks whether a request which failed with the given status code should be retried.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// This function will create an HTTPError for the given response and close the response body. Message of the error
+// response is added to the error if it can be read.
+func newHTTPError(url string, response *http.Response) *HTTPError {
+	defer response.Body.Close()
+	httpError := HTTPError{URL: url, StatusCode: response.StatusCode, Status: response.Status}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		httpError.Message = constant.TOO_MANY_REQUESTS_ERROR_MSG
+	default:
+		errorResponse := ErrorResponse{}
+		if data, err := ioutil.ReadAll(response.Body); err == nil && json.Unmarshal(data, &errorResponse) == nil {
+			httpError.Message = errorResponse.Error.Message
+		}
+	}
+	return &httpError
+}
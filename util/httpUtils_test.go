@@ -0,0 +1,186 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// This function will create a client which records the backoffs instead of waiting.
+func newTestHTTPClient(backoffs *[]time.Duration) *HTTPClient {
+	return &HTTPClient{
+		Client:         &http.Client{},
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		sleep: func(duration time.Duration) {
+			*backoffs = append(*backoffs, duration)
+		},
+	}
+}
+
+func TestHTTPClientRetries(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch requestCount {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set(constant.HEADER_RETRY_AFTER, "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	backoffs := make([]time.Duration, 0)
+	client := newTestHTTPClient(&backoffs)
+	request, err := http.NewRequest(http.MethodPost, server.URL, ioutil.NopCloser(strings.NewReader("payload")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || requestCount != 3 {
+		t.Errorf("Test failed, status: %d, requests: %d", response.StatusCode, requestCount)
+	}
+	if len(backoffs) != 2 || backoffs[0] != 100*time.Millisecond || backoffs[1] != time.Second {
+		t.Errorf("Test failed, unexpected backoffs: %v", backoffs)
+	}
+}
+
+func TestHTTPClientErrors(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if r.URL.Path == "/missing" {
+			writeDevServerError(w, http.StatusNotFound)
+			return
+		}
+		w.Header().Set(constant.HEADER_RETRY_AFTER, "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	backoffs := make([]time.Duration, 0)
+	client := newTestHTTPClient(&backoffs)
+	_, err := client.Get(server.URL + "/busy")
+	httpError, isHTTPError := err.(*HTTPError)
+	if !isHTTPError || httpError.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Test failed, expected HTTPError with 429, found %v", err)
+	}
+	// Requests are not retried if the Retry-After exceeds the maximum backoff
+	if requestCount != 1 || len(backoffs) != 0 {
+		t.Errorf("Test failed, requests: %d, backoffs: %v", requestCount, backoffs)
+	}
+
+	// Client errors are not retried
+	requestCount = 0
+	_, err = client.Get(server.URL + "/missing")
+	httpError, isHTTPError = err.(*HTTPError)
+	if !isHTTPError || httpError.StatusCode != http.StatusNotFound || httpError.Message != "Not Found" ||
+		requestCount != 1 {
+		t.Errorf("Test failed, unexpected error: %v, requests: %d", err, requestCount)
+	}
+
+	// Connection errors are retried and returned
+	backoffs = backoffs[:0]
+	if _, err = client.Get("http://127.0.0.1:0"); err == nil || len(backoffs) != 3 {
+		t.Errorf("Test failed, unexpected error: %v, backoffs: %v", err, backoffs)
+	}
+	backoffs = backoffs[:0]
+	if _, err = client.WithoutRetries().Get("http://127.0.0.1:0"); err == nil || len(backoffs) != 0 {
+		t.Errorf("Test failed, unexpected error: %v, backoffs: %v", err, backoffs)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if duration, found := parseRetryAfter("5"); !found || duration != 5*time.Second {
+		t.Errorf("Test failed, unexpected duration: %v", duration)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if duration, found := parseRetryAfter(date); !found || duration <= 0 || duration > time.Minute {
+		t.Errorf("Test failed, unexpected duration: %v", duration)
+	}
+	if _, found := parseRetryAfter("soon"); found {
+		t.Error("Test failed, invalid Retry-After accepted")
+	}
+}
+
+func TestNewHTTPClientWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+
+	// Certificate of the test server is not trusted by default
+	client, err := NewHTTPClient()
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if _, err = client.Get(server.URL); err == nil {
+		t.Error("Test failed, untrusted certificate accepted")
+	}
+
+	caBundle := filepath.Join(tempDir, "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err = ioutil.WriteFile(caBundle, certificate, 0644); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constant.HTTP_CLIENT_CA_BUNDLE, caBundle)
+	if client, err = NewHTTPClient(); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if data, err := client.Get(server.URL); err != nil || string(data) != "ok" {
+		t.Errorf("Test failed, unexpected response: %s, error: %v", string(data), err)
+	}
+
+	viper.Set(constant.HTTP_CLIENT_CA_BUNDLE, filepath.Join(tempDir, "missing.pem"))
+	if _, err = NewHTTPClient(); err == nil {
+		t.Error("Test failed, missing CA bundle accepted")
+	}
+	viper.Set(constant.HTTP_CLIENT_CA_BUNDLE, "")
+	viper.Set(constant.HTTP_CLIENT_PROXY, "http://[::1")
+	if _, err = NewHTTPClient(); err == nil {
+		t.Error("Test failed, invalid proxy accepted")
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+// Issue trackers which are used to look up the summaries of bug fixes.
+type IssueProvider interface {
+	// Returns whether the given JIRA_KEY or issue URL can be looked up using this provider
+	IsSupported(issue string) bool
+	// Returns the summary of the given JIRA_KEY or issue URL
+	GetSummary(issue string) (string, error)
+}
+
+// Looks up the summaries of Jira issues using the Jira REST API. Both JIRA_KEYs and browse URLs of the issues are
+// supported.
+type JiraIssueProvider struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+// Looks up the titles of GitHub issues using the GitHub REST API.
+type GitHubIssueProvider struct {
+	APIURL string
+	Token  string
+	Client *http.Client
+}
+
+// Structs to get the title field from the GitHub response
+type GitHubIssueResponse struct {
+	Title string `json:"title"`
+}
+
+// struct which is used to store the summaries of the issues which are already looked up. Key of the map is the
+// JIRA_KEY or the issue URL.
+type IssueSummaryCache struct {
+	location  string
+	Summaries map[string]string `yaml:"summaries"`
+}
+
+var (
+	jiraKeyRegex        = regexp.MustCompile(constant.JIRA_KEY_REGEX)
+	gitHubIssueURLRegex = regexp.MustCompile(constant.GITHUB_ISSUE_URL_REGEX)
+	issueSummaryCache   *IssueSummaryCache
+)
+
+// This function returns the issue providers configured in the config.yaml. Jira is only used if the jiraurl is
+// configured.
+func GetIssueProviders() []IssueProvider {
+	config := GetWUMUCConfigs()
+	providers := make([]IssueProvider, 0)
+	if len(config.JiraURL) > 0 {
+		providers = append(providers, &JiraIssueProvider{
+			BaseURL: config.JiraURL,
+			Token:   config.JiraToken,
+		})
+	}
+	gitHubAPIURL := config.GitHubAPIURL
+	if len(gitHubAPIURL) == 0 {
+		gitHubAPIURL = constant.GITHUB_API_URL
+	}
+	providers = append(providers, &GitHubIssueProvider{
+		APIURL: gitHubAPIURL,
+		Token:  config.GitHubToken,
+	})
+	return providers
+}
+
+// This function will get the summary of the given JIRA_KEY or issue URL using the configured issue providers. Summaries
+// are cached in $WUMUC_HOME. If the summary cannot be found, we just simply ignore the error and return the default
+// response.
+func GetIssueSummary(issue string) string {
+	if issueSummaryCache == nil {
+		issueSummaryCache = LoadIssueSummaryCache(filepath.Join(viper.GetString(constant.WUM_UC_HOME),
+			constant.WUMUC_CACHE_DIRECTORY, constant.ISSUE_SUMMARY_CACHE_FILE))
+	}
+	summary, err := LookupIssueSummary(issue, GetIssueProviders(), issueSummaryCache)
+	if err != nil {
+		logger.Debug(fmt.Sprintf("Error occurred while getting the summary of '%s': %v", issue, err))
+		return constant.JIRA_SUMMARY_DEFAULT
+	}
+	return summary
+}
+
+// This function will get the summary of the given JIRA_KEY or issue URL from the cache. If it is not cached, it is
+// looked up using the first provider which supports the issue and added to the cache.
+func LookupIssueSummary(issue string, providers []IssueProvider, cache *IssueSummaryCache) (string, error) {
+	issue = strings.TrimSpace(issue)
+	if summary, found := cache.Get(issue); found {
+		logger.Debug(fmt.Sprintf("Summary of '%s' found in the cache", issue))
+		return summary, nil
+	}
+	for _, provider := range providers {
+		if !provider.IsSupported(issue) {
+			continue
+		}
+		summary, err := provider.GetSummary(issue)
+		if err != nil {
+			return "", err
+		}
+		if err = cache.Put(issue, summary); err != nil {
+			logger.Debug(fmt.Sprintf("Error occurred while caching the summary of '%s': %v", issue, err))
+		}
+		return summary, nil
+	}
+	return "", errors.New(fmt.Sprintf("issue tracker not configured for '%s'", issue))
+}
+
+// This function checks whether the given issue is a JIRA_KEY or a browse URL of the configured Jira.
+func (provider *JiraIssueProvider) IsSupported(issue string) bool {
+	return len(provider.BaseURL) > 0 && len(provider.getJiraKey(issue)) > 0
+}
+
+// This function returns the JIRA_KEY of the given issue. An empty string is returned if the issue is not a JIRA_KEY
+// or a browse URL of the configured Jira.
+func (provider *JiraIssueProvider) getJiraKey(issue string) string {
+	browseURLPrefix := strings.TrimSuffix(provider.BaseURL, "/") + constant.JIRA_BROWSE_CONTEXT
+	issue = strings.TrimSuffix(strings.TrimPrefix(issue, browseURLPrefix), "/")
+	if jiraKeyRegex.MatchString(issue) {
+		return issue
+	}
+	return ""
+}
+
+// This function will get the summary of the given issue using the Jira REST API.
+func (provider *JiraIssueProvider) GetSummary(issue string) (string, error) {
+	url := strings.TrimSuffix(provider.BaseURL, "/") + constant.JIRA_ISSUE_API_CONTEXT +
+		provider.getJiraKey(issue) + "?fields=summary"
+	jiraResponse := JiraResponse{}
+	if err := getIssueResponse(provider.Client, url, "Bearer", provider.Token, &jiraResponse); err != nil {
+		return "", err
+	}
+	if len(jiraResponse.Fields.Summary) == 0 {
+		return "", errors.New("summary field not found in the jira response")
+	}
+	return jiraResponse.Fields.Summary, nil
+}
+
+// This function checks whether the given issue is a GitHub issue URL.
+func (provider *GitHubIssueProvider) IsSupported(issue string) bool {
+	return gitHubIssueURLRegex.MatchString(issue)
+}
+
+// This function will get the title of the given GitHub issue using the GitHub REST API.
+func (provider *GitHubIssueProvider) GetSummary(issue string) (string, error) {
+	match := gitHubIssueURLRegex.FindStringSubmatch(issue)
+	if match == nil {
+		return "", errors.New(fmt.Sprintf("'%s' is not a GitHub issue URL", issue))
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%s", strings.TrimSuffix(provider.APIURL, "/"), match[1],
+		match[2], match[3])
+	gitHubResponse := GitHubIssueResponse{}
+	if err := getIssueResponse(provider.Client, url, "token", provider.Token, &gitHubResponse); err != nil {
+		return "", err
+	}
+	if len(gitHubResponse.Title) == 0 {
+		return "", errors.New("title field not found in the GitHub response")
+	}
+	return gitHubResponse.Title, nil
+}
+
+// This function will send a GET request to the given issue tracker URL and unmarshal the json response to the given
+// struct. If a token is given, it is sent in the Authorization header using the given scheme. Shared HTTP client is
+// used if a client is not given.
+func getIssueResponse(client *http.Client, url, authorizationScheme, token string, response interface{}) error {
+	httpClient := GetHTTPClient()
+	if client != nil {
+		httpClient = &HTTPClient{Client: client}
+	}
+	logger.Debug(fmt.Sprintf("Requesting %s", url))
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if len(token) > 0 {
+		request.Header.Set("Authorization", authorizationScheme+" "+token)
+	}
+	res, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return newHTTPError(url, res)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	logger.Trace(fmt.Sprintf("Response body: %s", string(body)))
+	return json.Unmarshal(body, response)
+}
+
+// This function will read the issue summary cache in the given location. An empty cache is returned if the file does
+// not exist or cannot be read.
+func LoadIssueSummaryCache(location string) *IssueSummaryCache {
+	cache := IssueSummaryCache{location: location}
+	data, err := ioutil.ReadFile(location)
+	if err == nil {
+		if err = yaml.Unmarshal(data, &cache); err != nil {
+			logger.Debug(fmt.Sprintf("Ignoring the invalid issue summary cache '%s': %v", location, err))
+		}
+	}
+	if cache.Summaries == nil {
+		cache.Summaries = make(map[string]string)
+	}
+	return &cache
+}
+
+// This function returns the cached summary of the given issue.
+func (cache *IssueSummaryCache) Get(issue string) (string, bool) {
+	summary, found := cache.Summaries[issue]
+	return summary, found
+}
+
+// This function will add the given summary to the cache and save the cache.
+func (cache *IssueSummaryCache) Put(issue, summary string) error {
+	cache.Summaries[issue] = summary
+	if len(cache.location) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cache.location), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cache.location, data, 0600)
+}
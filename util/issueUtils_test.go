@@ -0,0 +1,124 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// This function starts a local HTTP stub of Jira and GitHub. The number of requests received is counted in the given
+// counter.
+func startIssueTrackerStub(requestCount *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requestCount++
+		switch r.URL.Path {
+		case "/rest/api/2/issue/CARBON-1234":
+			if r.Header.Get("Authorization") != "Bearer jira-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"key": "CARBON-1234", "fields": {"summary": "Login fails"}}`))
+		case "/repos/wso2/carbon-kernel/issues/42":
+			if r.Header.Get("Authorization") != "token github-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"number": 42, "title": "Logout fails"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestLookupIssueSummary(t *testing.T) {
+	requestCount := 0
+	server := startIssueTrackerStub(&requestCount)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	cacheLocation := filepath.Join(tempDir, "cache", "issue-summaries.yaml")
+
+	providers := []IssueProvider{
+		&JiraIssueProvider{BaseURL: server.URL + "/", Token: "jira-token"},
+		&GitHubIssueProvider{APIURL: server.URL, Token: "github-token"},
+	}
+	cache := LoadIssueSummaryCache(cacheLocation)
+
+	testData := []struct {
+		issue    string
+		expected string
+	}{
+		{"CARBON-1234", "Login fails"},
+		{server.URL + "/browse/CARBON-1234", "Login fails"},
+		{"https://github.com/wso2/carbon-kernel/issues/42", "Logout fails"},
+	}
+	for _, data := range testData {
+		summary, err := LookupIssueSummary(data.issue, providers, cache)
+		if err != nil {
+			t.Errorf("Test failed for %s, unexpected error: %v", data.issue, err)
+		} else if summary != data.expected {
+			t.Errorf("Test failed for %s, expected: %s, actual: %s", data.issue, data.expected, summary)
+		}
+	}
+	if requestCount != 3 {
+		t.Errorf("Test failed, expected 3 requests, actual: %d", requestCount)
+	}
+
+	//Summaries should be read from the cache saved in the disk
+	cache = LoadIssueSummaryCache(cacheLocation)
+	summary, err := LookupIssueSummary("CARBON-1234", providers, cache)
+	if err != nil || summary != "Login fails" {
+		t.Errorf("Test failed, expected: Login fails, actual: %s, %v", summary, err)
+	}
+	if requestCount != 3 {
+		t.Errorf("Test failed, cached summary was requested again, requests: %d", requestCount)
+	}
+
+	//Errors should not be cached
+	for _, issue := range []string{"CARBON-9999", "https://github.com/wso2/carbon-kernel/issues/1", "not-an-issue"} {
+		if _, err = LookupIssueSummary(issue, providers, cache); err == nil {
+			t.Errorf("Test failed, expected an error for %s", issue)
+		}
+		if _, found := cache.Get(issue); found {
+			t.Errorf("Test failed, %s should not be cached", issue)
+		}
+	}
+}
+
+func TestIssueProviderAuthentication(t *testing.T) {
+	requestCount := 0
+	server := startIssueTrackerStub(&requestCount)
+	defer server.Close()
+
+	provider := &JiraIssueProvider{BaseURL: server.URL}
+	if _, err := provider.GetSummary("CARBON-1234"); err == nil {
+		t.Errorf("Test failed, expected an error as the token is not sent")
+	}
+
+	//Jira keys are not supported if Jira is not configured
+	provider = &JiraIssueProvider{}
+	if provider.IsSupported("CARBON-1234") {
+		t.Errorf("Test failed, Jira keys should not be supported if Jira is not configured")
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+// This struct is used to store the OSGi bundle details read from the MANIFEST.MF of a jar.
+type BundleInfo struct {
+	SymbolicName string
+	Version      string
+}
+
+// This struct is used to cache the highest class versions of distributions. Key of the versions is the checksum of the
+// distribution.
+type ClassVersionCache struct {
+	location string
+	Versions map[string]int `yaml:"versions"`
+}
+
+// This struct is used to store the differences of the entries of two versions of a jar.
+type JarEntryChanges struct {
+	AddedEntries    []string
+	RemovedEntries  []string
+	ModifiedEntries []string
+}
+
+// This function will read the OSGi bundle details of the jar file in the given location. If the jar is not an OSGi
+// bundle, nil is returned.
+func GetBundleInfoOfFile(jarPath string) (*BundleInfo, error) {
+	data, err := ioutil.ReadFile(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	return GetBundleInfo(data)
+}
+
+// This function will read the OSGi bundle details from the content of a jar file. If the jar does not have a
+// MANIFEST.MF or the manifest does not have a Bundle-SymbolicName header, nil is returned.
+func GetBundleInfo(jarData []byte) (*BundleInfo, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(jarData), int64(len(jarData)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range zipReader.File {
+		if file.Name != constant.JAR_MANIFEST_FILE {
+			continue
+		}
+		manifestFile, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		manifestData, err := ioutil.ReadAll(manifestFile)
+		manifestFile.Close()
+		if err != nil {
+			return nil, err
+		}
+		headers := ParseManifest(manifestData)
+		symbolicName := headers[constant.BUNDLE_SYMBOLIC_NAME_HEADER]
+		if len(symbolicName) == 0 {
+			return nil, nil
+		}
+		// Bundle-SymbolicName can have directives like 'singleton:=true' after the name.
+		if index := strings.Index(symbolicName, ";"); index > -1 {
+			symbolicName = symbolicName[:index]
+		}
+		return &BundleInfo{
+			SymbolicName: strings.TrimSpace(symbolicName),
+			Version:      strings.TrimSpace(headers[constant.BUNDLE_VERSION_HEADER]),
+		}, nil
+	}
+	return nil, nil
+}
+
+// This function will parse the main section of the given MANIFEST.MF content and return the headers as a map.
+// Lines starting with a single space are continuations of the previous header value as per the jar specification.
+func ParseManifest(data []byte) map[string]string {
+	headers := make(map[string]string)
+	content := strings.Replace(string(data), "\r\n", "\n", -1)
+	content = strings.Replace(content, "\r", "\n", -1)
+	lastHeader := ""
+	for _, line := range strings.Split(content, "\n") {
+		// An empty line marks the end of the main section
+		if len(line) == 0 {
+			if len(headers) > 0 {
+				break
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") {
+			if len(lastHeader) != 0 {
+				headers[lastHeader] = headers[lastHeader] + line[1:]
+			}
+			continue
+		}
+		index := strings.Index(line, ":")
+		if index < 0 {
+			logger.Trace(fmt.Sprintf("Invalid manifest line: %s", line))
+			continue
+		}
+		lastHeader = strings.TrimSpace(line[:index])
+		headers[lastHeader] = strings.TrimSpace(line[index+1:])
+	}
+	return headers
+}
+
+// This function will compare the given OSGi bundle versions (major.minor.micro.qualifier). It returns a negative
+// value if version1 is lower than version2, 0 if both are equal and a positive value otherwise. Missing numeric
+// segments are considered as 0 and qualifiers are compared lexically.
+func CompareBundleVersions(version1, version2 string) int {
+	segments1 := strings.SplitN(strings.TrimSpace(version1), ".", 4)
+	segments2 := strings.SplitN(strings.TrimSpace(version2), ".", 4)
+	for i := 0; i < 3; i++ {
+		number1 := getVersionSegment(segments1, i)
+		number2 := getVersionSegment(segments2, i)
+		if number1 != number2 {
+			return number1 - number2
+		}
+	}
+	return strings.Compare(getVersionQualifier(segments1), getVersionQualifier(segments2))
+}
+
+// This function returns the numeric value of the version segment in the given index.
+func getVersionSegment(segments []string, index int) int {
+	if index >= len(segments) {
+		return 0
+	}
+	number, err := strconv.Atoi(segments[index])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// This function returns the qualifier of the given version segments.
+func getVersionQualifier(segments []string) string {
+	if len(segments) < 4 {
+		return ""
+	}
+	return segments[3]
+}
+
+// This function will read the CRC32 checksums of all the file entries of the jar file in the given location.
+func GetJarEntriesOfFile(jarPath string) (map[string]uint32, error) {
+	data, err := ioutil.ReadFile(jarPath)
+	if err != nil {
+		return nil, err
+	}
+	return GetJarEntries(data)
+}
+
+// This function will read the CRC32 checksums of all the file entries from the content of a jar file. Key of the
+// returned map is the name of the entry. Checksums are read from the central directory of the jar, so the entries are
+// not decompressed.
+func GetJarEntries(jarData []byte) (map[string]uint32, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(jarData), int64(len(jarData)))
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]uint32)
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		entries[file.Name] = file.CRC32
+	}
+	return entries, nil
+}
+
+// This function will compare the entries of the old and new versions of a jar and return the added, removed and
+// modified entries in sorted order.
+func CompareJarEntries(oldEntries, newEntries map[string]uint32) *JarEntryChanges {
+	changes := JarEntryChanges{
+		AddedEntries:    make([]string, 0),
+		RemovedEntries:  make([]string, 0),
+		ModifiedEntries: make([]string, 0),
+	}
+	for name, newCRC := range newEntries {
+		oldCRC, found := oldEntries[name]
+		if !found {
+			changes.AddedEntries = append(changes.AddedEntries, name)
+		} else if oldCRC != newCRC {
+			changes.ModifiedEntries = append(changes.ModifiedEntries, name)
+		}
+	}
+	for name := range oldEntries {
+		if _, found := newEntries[name]; !found {
+			changes.RemovedEntries = append(changes.RemovedEntries, name)
+		}
+	}
+	sort.Strings(changes.AddedEntries)
+	sort.Strings(changes.RemovedEntries)
+	sort.Strings(changes.ModifiedEntries)
+	return &changes
+}
+
+// This function checks whether there are any changes.
+func (changes *JarEntryChanges) IsEmpty() bool {
+	return len(changes.AddedEntries) == 0 && len(changes.RemovedEntries) == 0 && len(changes.ModifiedEntries) == 0
+}
+
+// This function will print the given changes of the jar in the given location. Class entries and resource entries
+// are printed separately.
+func PrintJarEntryChanges(jarPath string, changes *JarEntryChanges) {
+	if changes.IsEmpty() {
+		PrintInfo(fmt.Sprintf("No entries have been changed in '%s'.", jarPath))
+		return
+	}
+	PrintInfo(fmt.Sprintf("Entries changed in '%s':", jarPath))
+	printJarEntries("Added classes", filterClassEntries(changes.AddedEntries, true))
+	printJarEntries("Removed classes", filterClassEntries(changes.RemovedEntries, true))
+	printJarEntries("Modified classes", filterClassEntries(changes.ModifiedEntries, true))
+	printJarEntries("Added resources", filterClassEntries(changes.AddedEntries, false))
+	printJarEntries("Removed resources", filterClassEntries(changes.RemovedEntries, false))
+	printJarEntries("Modified resources", filterClassEntries(changes.ModifiedEntries, false))
+}
+
+// This function will print the given entries with the given title if there are any entries.
+func printJarEntries(title string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Println(fmt.Sprintf("\t%s (%d):", title, len(entries)))
+	for _, entry := range entries {
+		fmt.Println(fmt.Sprintf("\t\t%s", entry))
+	}
+}
+
+// This function will return the class entries if isClass is true. Otherwise the resource entries are returned.
+func filterClassEntries(entries []string, isClass bool) []string {
+	filteredEntries := make([]string, 0)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry, constant.CLASS_EXTENSION) == isClass {
+			filteredEntries = append(filteredEntries, entry)
+		}
+	}
+	return filteredEntries
+}
+
+// This function will read the major version from the header of the given class file content.
+func GetClassVersion(classData []byte) (int, error) {
+	if len(classData) < 8 || binary.BigEndian.Uint32(classData[0:4]) != constant.CLASS_FILE_MAGIC {
+		return 0, errors.New("invalid class file")
+	}
+	return int(binary.BigEndian.Uint16(classData[6:8])), nil
+}
+
+// This function will read the class file major versions of all the classes in the given jar content and return the
+// highest version along with the name of a class which has that version. If there are no classes in the jar, 0 is
+// returned as the version. Classes of multi-release jars (ie. META-INF/versions/N/) and module-info.class are skipped
+// as they are only loaded by the Java versions which support them.
+func GetMaxClassVersion(jarData []byte) (int, string, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(jarData), int64(len(jarData)))
+	if err != nil {
+		return 0, "", err
+	}
+	maxVersion := 0
+	maxVersionClass := ""
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, constant.CLASS_EXTENSION) ||
+			strings.HasPrefix(file.Name, constant.JAR_VERSIONS_DIRECTORY) ||
+			path.Base(file.Name) == constant.MODULE_INFO_CLASS {
+			continue
+		}
+		classFile, err := file.Open()
+		if err != nil {
+			return 0, "", err
+		}
+		// Only the header of the class file is needed
+		header := make([]byte, 8)
+		_, err = io.ReadFull(classFile, header)
+		classFile.Close()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Error occurred while reading the header of %s: %v", file.Name, err))
+			continue
+		}
+		version, err := GetClassVersion(header)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("Error occurred while reading the version of %s: %v", file.Name, err))
+			continue
+		}
+		if version > maxVersion {
+			maxVersion = version
+			maxVersionClass = file.Name
+		}
+	}
+	return maxVersion, maxVersionClass, nil
+}
+
+// This function returns the Java version of the given class file major version (ie. 52 -> 8).
+func GetJavaVersion(classVersion int) string {
+	switch {
+	case classVersion < 45:
+		return "unknown"
+	case classVersion < 49:
+		return fmt.Sprintf("1.%d", classVersion-44)
+	default:
+		return strconv.Itoa(classVersion - 44)
+	}
+}
+
+// This function will load the class version cache in the given location. An empty cache is returned if the cache is not
+// found or invalid. If the location is empty, the cache is not saved.
+func LoadClassVersionCache(location string) *ClassVersionCache {
+	cache := ClassVersionCache{location: location}
+	if len(location) > 0 {
+		data, err := ioutil.ReadFile(location)
+		if err == nil {
+			if err = yaml.Unmarshal(data, &cache); err != nil {
+				logger.Debug(fmt.Sprintf("Ignoring the invalid class version cache '%s': %v", location, err))
+			}
+		}
+	}
+	if cache.Versions == nil {
+		cache.Versions = make(map[string]int)
+	}
+	return &cache
+}
+
+// This function returns the cached class version of the distribution with the given checksum.
+func (cache *ClassVersionCache) Get(checksum string) (int, bool) {
+	version, found := cache.Versions[checksum]
+	return version, found
+}
+
+// This function will add the given class version to the cache and save the cache.
+func (cache *ClassVersionCache) Put(checksum string, version int) error {
+	cache.Versions[checksum] = version
+	if len(cache.location) == 0 {
+		return nil
+	}
+	data, err := yaml.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cache.location), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cache.location, data, 0600)
+}
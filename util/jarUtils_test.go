@@ -0,0 +1,192 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+// This function creates a jar in memory with the given manifest content.
+func createJar(t *testing.T, manifest string) []byte {
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	file, err := writer.Create("META-INF/MANIFEST.MF")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating the jar: %v", err)
+	}
+	file.Write([]byte(manifest))
+	writer.Close()
+	return buffer.Bytes()
+}
+
+// This function creates a jar with the given entries. Key of the entries map is the name of the entry and the value is
+// the content.
+func createJarWithEntries(t *testing.T, entries map[string]string) []byte {
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+	for name, content := range entries {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("Test failed, error occurred while creating the jar: %v", err)
+		}
+		file.Write([]byte(content))
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func TestParseManifest(t *testing.T) {
+	manifest := "Manifest-Version: 1.0\r\nBundle-SymbolicName: org.wso2.carbon.very.long.bundle.name.that.conti\r\n" +
+		" nues;singleton:=true\r\nBundle-Version: 4.4.12\r\n\r\nName: other\r\nBundle-Version: 1.0.0\r\n"
+	headers := ParseManifest([]byte(manifest))
+	expected := "org.wso2.carbon.very.long.bundle.name.that.continues;singleton:=true"
+	if headers["Bundle-SymbolicName"] != expected {
+		t.Errorf("Test failed, expected: %s, actual: %s", expected, headers["Bundle-SymbolicName"])
+	}
+	if headers["Bundle-Version"] != "4.4.12" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "4.4.12", headers["Bundle-Version"])
+	}
+}
+
+func TestGetBundleInfo(t *testing.T) {
+	jar := createJar(t, "Manifest-Version: 1.0\nBundle-SymbolicName: org.wso2.carbon.foo;singleton:=true\n"+
+		"Bundle-Version: 4.4.12\n")
+	bundleInfo, err := GetBundleInfo(jar)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if bundleInfo == nil {
+		t.Fatalf("Test failed, bundle details not found")
+	}
+	if bundleInfo.SymbolicName != "org.wso2.carbon.foo" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "org.wso2.carbon.foo", bundleInfo.SymbolicName)
+	}
+	if bundleInfo.Version != "4.4.12" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "4.4.12", bundleInfo.Version)
+	}
+
+	// Plain jars are not bundles
+	jar = createJar(t, "Manifest-Version: 1.0\n")
+	bundleInfo, err = GetBundleInfo(jar)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if bundleInfo != nil {
+		t.Errorf("Test failed, expected: %v, actual: %v", nil, bundleInfo)
+	}
+}
+
+func TestCompareBundleVersions(t *testing.T) {
+	if CompareBundleVersions("4.4.12", "4.4.10") <= 0 {
+		t.Errorf("Test failed, 4.4.12 should be greater than 4.4.10")
+	}
+	if CompareBundleVersions("4.4.9", "4.4.10") >= 0 {
+		t.Errorf("Test failed, 4.4.9 should be less than 4.4.10")
+	}
+	if CompareBundleVersions("1.6.1.wso2v16", "1.6.1.wso2v15") <= 0 {
+		t.Errorf("Test failed, 1.6.1.wso2v16 should be greater than 1.6.1.wso2v15")
+	}
+	if CompareBundleVersions("2.0", "2.0.0") != 0 {
+		t.Errorf("Test failed, 2.0 should be equal to 2.0.0")
+	}
+}
+
+func TestCompareJarEntries(t *testing.T) {
+	oldEntries, err := GetJarEntries(createJarWithEntries(t, map[string]string{
+		"META-INF/MANIFEST.MF":   "Manifest-Version: 1.0\n",
+		"org/wso2/A.class":       "a",
+		"org/wso2/B.class":       "b",
+		"org/wso2/config.xml":    "<config/>",
+		"org/wso2/removed.class": "removed",
+	}))
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	newEntries, err := GetJarEntries(createJarWithEntries(t, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n",
+		"org/wso2/A.class":     "a",
+		"org/wso2/B.class":     "b modified",
+		"org/wso2/config.xml":  "<config/>",
+		"org/wso2/C.class":     "c",
+	}))
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+
+	changes := CompareJarEntries(oldEntries, newEntries)
+	if len(changes.AddedEntries) != 1 || changes.AddedEntries[0] != "org/wso2/C.class" {
+		t.Errorf("Test failed, unexpected added entries: %v", changes.AddedEntries)
+	}
+	if len(changes.RemovedEntries) != 1 || changes.RemovedEntries[0] != "org/wso2/removed.class" {
+		t.Errorf("Test failed, unexpected removed entries: %v", changes.RemovedEntries)
+	}
+	if len(changes.ModifiedEntries) != 1 || changes.ModifiedEntries[0] != "org/wso2/B.class" {
+		t.Errorf("Test failed, unexpected modified entries: %v", changes.ModifiedEntries)
+	}
+	if !CompareJarEntries(oldEntries, oldEntries).IsEmpty() {
+		t.Errorf("Test failed, no changes expected when comparing the same entries")
+	}
+}
+
+// This function returns the header of a class file with the given major version.
+func createClassHeader(majorVersion int) string {
+	return string([]byte{0xCA, 0xFE, 0xBA, 0xBE, 0x00, 0x00, byte(majorVersion >> 8), byte(majorVersion)})
+}
+
+func TestGetMaxClassVersion(t *testing.T) {
+	jar := createJarWithEntries(t, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\n",
+		"org/wso2/A.class":     createClassHeader(51),
+		"org/wso2/B.class":     createClassHeader(52) + "body",
+		"org/wso2/C.class":     "invalid",
+		// Following classes are not loaded by older Java versions
+		"module-info.class":                     createClassHeader(53),
+		"META-INF/versions/11/org/wso2/B.class": createClassHeader(55),
+	})
+	version, className, err := GetMaxClassVersion(jar)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if version != 52 {
+		t.Errorf("Test failed, expected: %d, actual: %d", 52, version)
+	}
+	if className != "org/wso2/B.class" {
+		t.Errorf("Test failed, expected: %s, actual: %s", "org/wso2/B.class", className)
+	}
+}
+
+func TestGetClassVersion(t *testing.T) {
+	if _, err := GetClassVersion([]byte("not a class")); err == nil {
+		t.Errorf("Test failed, error expected for invalid class file")
+	}
+	version, err := GetClassVersion([]byte(createClassHeader(55)))
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if version != 55 {
+		t.Errorf("Test failed, expected: %d, actual: %d", 55, version)
+	}
+}
+
+func TestGetJavaVersion(t *testing.T) {
+	versions := map[int]string{46: "1.2", 48: "1.4", 49: "5", 52: "8", 55: "11"}
+	for classVersion, expected := range versions {
+		if actual := GetJavaVersion(classVersion); actual != expected {
+			t.Errorf("Test failed, expected: %s, actual: %s", expected, actual)
+		}
+	}
+}
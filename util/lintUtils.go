@@ -0,0 +1,260 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// struct which is used to read a lint rule from the config.yaml. Targets can be fields of the update-descriptor3.yaml
+// (description, instructions, bug_fix_keys and bug_fix_summaries) or names of the resource files (ie.
+// instructions.txt). If a condition is given, the rule is only applied if the condition is true for the update.
+type LintRule struct {
+	Name      string   `mapstructure:"name" yaml:"name"`
+	Type      string   `mapstructure:"type" yaml:"type"`
+	Severity  string   `mapstructure:"severity" yaml:"severity"`
+	Targets   []string `mapstructure:"targets" yaml:"targets"`
+	Pattern   string   `mapstructure:"pattern" yaml:"pattern,omitempty"`
+	MaxLength int      `mapstructure:"max_length" yaml:"max_length,omitempty"`
+	Condition string   `mapstructure:"condition" yaml:"condition,omitempty"`
+	Message   string   `mapstructure:"message" yaml:"message,omitempty"`
+}
+
+// struct which is used to store a violation of a lint rule
+type LintViolation struct {
+	Rule     string
+	Severity string
+	Target   string
+	Message  string
+	// Lines or values which violate the rule
+	Details []string
+}
+
+// struct which is used to store the content which is linted. Key of the values map is the target name.
+type LintContext struct {
+	values     map[string][]string
+	conditions map[string]bool
+}
+
+// This function returns the lint rules configured in the config.yaml. Default rules are returned if the rules are not
+// configured.
+func GetLintRules() ([]LintRule, error) {
+	rules := make([]LintRule, 0)
+	if err := viper.UnmarshalKey(constant.LINT_RULES, &rules); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid %s found in the config. %v", constant.LINT_RULES, err))
+	}
+	return rules, nil
+}
+
+// This function will create a new lint context with the fields of the given update descriptor.
+func NewLintContext(updateDescriptor *UpdateDescriptorV3) *LintContext {
+	context := LintContext{
+		values:     make(map[string][]string),
+		conditions: make(map[string]bool),
+	}
+	if updateDescriptor == nil {
+		return &context
+	}
+	context.values[constant.LINT_TARGET_DESCRIPTION] = []string{updateDescriptor.Description}
+	context.values[constant.LINT_TARGET_INSTRUCTIONS] = []string{updateDescriptor.Instructions}
+	bugFixKeys := make([]string, 0)
+	for bugFixKey := range updateDescriptor.BugFixes {
+		bugFixKeys = append(bugFixKeys, bugFixKey)
+	}
+	sort.Strings(bugFixKeys)
+	bugFixSummaries := make([]string, 0)
+	for _, bugFixKey := range bugFixKeys {
+		bugFixSummaries = append(bugFixSummaries, updateDescriptor.BugFixes[bugFixKey])
+	}
+	context.values[constant.LINT_TARGET_BUG_FIX_KEYS] = bugFixKeys
+	context.values[constant.LINT_TARGET_BUG_FIX_SUMMARIES] = bugFixSummaries
+	context.conditions[constant.LINT_CONDITION_JARS_CHANGED] = isJarChanged(updateDescriptor)
+	return &context
+}
+
+// This function checks whether any jar is added or modified by the given update.
+func isJarChanged(updateDescriptor *UpdateDescriptorV3) bool {
+	products := append(append([]ProductChanges{}, updateDescriptor.CompatibleProducts...),
+		updateDescriptor.PartiallyApplicableProducts...)
+	for _, product := range products {
+		for _, filePath := range append(append([]string{}, product.AddedFiles...), product.ModifiedFiles...) {
+			if strings.HasSuffix(filePath, constant.JAR_EXTENSION) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// This function will add the content of the given file to the lint context. The file name is used as the target name.
+func (context *LintContext) AddFile(fileName string, data []byte) {
+	context.values[fileName] = []string{string(data)}
+}
+
+// This function will apply the given rules to the content of the lint context. Targets which are not found in the
+// context are ignored. An error is returned if a rule is invalid.
+func ApplyLintRules(rules []LintRule, context *LintContext) ([]LintViolation, error) {
+	violations := make([]LintViolation, 0)
+	for _, rule := range rules {
+		if err := validateLintRule(&rule); err != nil {
+			return nil, err
+		}
+		if len(rule.Condition) > 0 && !context.conditions[rule.Condition] {
+			logger.Debug(fmt.Sprintf("Condition '%s' of the lint rule '%s' is not met", rule.Condition, rule.Name))
+			continue
+		}
+		for _, target := range rule.Targets {
+			values, found := context.values[target]
+			if !found {
+				continue
+			}
+			details, violated := applyLintRule(&rule, values)
+			if !violated {
+				continue
+			}
+			violations = append(violations, LintViolation{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Target:   target,
+				Message:  getLintMessage(&rule),
+				Details:  details,
+			})
+		}
+	}
+	return violations, nil
+}
+
+// This function will validate the given rule and set the default severity if it is not given.
+func validateLintRule(rule *LintRule) error {
+	if len(rule.Name) == 0 {
+		return errors.New("name of a lint rule is empty")
+	}
+	if len(rule.Severity) == 0 {
+		rule.Severity = constant.LINT_SEVERITY_WARNING
+	}
+	severities := []string{constant.LINT_SEVERITY_ERROR, constant.LINT_SEVERITY_WARNING, constant.LINT_SEVERITY_INFO}
+	if !IsStringIsInSlice(rule.Severity, severities) {
+		return errors.New(fmt.Sprintf("invalid severity '%s' found in the lint rule '%s'. Supported severities "+
+			"are %v", rule.Severity, rule.Name, severities))
+	}
+	switch rule.Type {
+	case constant.LINT_RULE_FORBIDDEN, constant.LINT_RULE_REQUIRED, constant.LINT_RULE_FORMAT:
+		if _, err := regexp.Compile(rule.Pattern); err != nil || len(rule.Pattern) == 0 {
+			return errors.New(fmt.Sprintf("invalid pattern '%s' found in the lint rule '%s'. %v", rule.Pattern,
+				rule.Name, err))
+		}
+	case constant.LINT_RULE_MAX_LENGTH:
+		if rule.MaxLength <= 0 {
+			return errors.New(fmt.Sprintf("max_length of the lint rule '%s' should be greater than 0",
+				rule.Name))
+		}
+	default:
+		return errors.New(fmt.Sprintf("invalid type '%s' found in the lint rule '%s'. Supported types are %v",
+			rule.Type, rule.Name, []string{constant.LINT_RULE_FORBIDDEN, constant.LINT_RULE_REQUIRED,
+				constant.LINT_RULE_FORMAT, constant.LINT_RULE_MAX_LENGTH}))
+	}
+	return nil
+}
+
+// This function will apply the given rule to the given values. Values which violate the rule are returned.
+func applyLintRule(rule *LintRule, values []string) ([]string, bool) {
+	details := make([]string, 0)
+	switch rule.Type {
+	case constant.LINT_RULE_FORBIDDEN:
+		regex := regexp.MustCompile(rule.Pattern)
+		for _, value := range values {
+			for _, line := range SplitLines(value) {
+				if regex.MatchString(line) {
+					details = append(details, strings.TrimSpace(line))
+				}
+			}
+		}
+		return details, len(details) > 0
+	case constant.LINT_RULE_REQUIRED:
+		regex := regexp.MustCompile(rule.Pattern)
+		for _, value := range values {
+			if regex.MatchString(value) {
+				return details, false
+			}
+		}
+		return details, true
+	case constant.LINT_RULE_FORMAT:
+		// The whole value should match the pattern
+		regex := regexp.MustCompile("^(?:" + rule.Pattern + ")$")
+		for _, value := range values {
+			if !regex.MatchString(value) {
+				details = append(details, value)
+			}
+		}
+		return details, len(details) > 0
+	case constant.LINT_RULE_MAX_LENGTH:
+		for _, value := range values {
+			if len(value) > rule.MaxLength {
+				details = append(details, fmt.Sprintf("%d characters", len(value)))
+			}
+		}
+		return details, len(details) > 0
+	}
+	return details, false
+}
+
+// This function returns the message of the given rule. A default message is returned if the message is not given.
+func getLintMessage(rule *LintRule) string {
+	if len(rule.Message) > 0 {
+		return rule.Message
+	}
+	switch rule.Type {
+	case constant.LINT_RULE_FORBIDDEN:
+		return fmt.Sprintf("should not match '%s'", rule.Pattern)
+	case constant.LINT_RULE_REQUIRED:
+		return fmt.Sprintf("should match '%s'", rule.Pattern)
+	case constant.LINT_RULE_FORMAT:
+		return fmt.Sprintf("should be in the format '%s'", rule.Pattern)
+	default:
+		return fmt.Sprintf("should not be longer than %d characters", rule.MaxLength)
+	}
+}
+
+// This function will print the given violations. An error is returned if there are violations with the error severity.
+func ReportLintViolations(violations []LintViolation) error {
+	errorCount := 0
+	for _, violation := range violations {
+		message := fmt.Sprintf("[%s] '%s' %s", violation.Rule, violation.Target, violation.Message)
+		switch violation.Severity {
+		case constant.LINT_SEVERITY_ERROR:
+			errorCount++
+			PrintErrorWithTab(message)
+		case constant.LINT_SEVERITY_WARNING:
+			PrintWarning(message)
+		default:
+			PrintInfo(message)
+		}
+		for i, detail := range violation.Details {
+			PrintInfo(fmt.Sprintf("#%d - %v", i+1, detail))
+		}
+	}
+	if errorCount > 0 {
+		return errors.New(fmt.Sprintf("%d lint rule(s) with the '%s' severity failed.", errorCount,
+			constant.LINT_SEVERITY_ERROR))
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+func getTestUpdateDescriptor() *UpdateDescriptorV3 {
+	return &UpdateDescriptorV3{
+		Description:  "Fixes the login issue of the management console",
+		Instructions: "Apply the update using WUM.",
+		BugFixes: map[string]string{
+			"CARBON-1234": "Login fails",
+			"carbon_5678": "Logout fails",
+		},
+		CompatibleProducts: []ProductChanges{
+			{
+				ProductName:   "wso2am",
+				ModifiedFiles: []string{"repository/components/plugins/org.wso2.carbon.ui_4.4.10.jar"},
+			},
+		},
+	}
+}
+
+func TestApplyLintRules(t *testing.T) {
+	lintContext := NewLintContext(getTestUpdateDescriptor())
+	lintContext.AddFile(constant.INSTRUCTIONS_FILE, []byte("Copy the patch to the server.\nRestart the server."))
+
+	testData := []struct {
+		rule     LintRule
+		expected []string
+	}{
+		{LintRule{Name: "forbidden", Type: constant.LINT_RULE_FORBIDDEN, Pattern: constant.PATCH_REGEX,
+			Targets: []string{constant.INSTRUCTIONS_FILE, constant.LINT_TARGET_DESCRIPTION}},
+			[]string{"Copy the patch to the server."}},
+		{LintRule{Name: "required-section", Type: constant.LINT_RULE_REQUIRED, Pattern: "(?m)^Prerequisites",
+			Targets: []string{constant.INSTRUCTIONS_FILE}}, []string{}},
+		{LintRule{Name: "required-present", Type: constant.LINT_RULE_REQUIRED, Pattern: "(?i)restart",
+			Targets: []string{constant.INSTRUCTIONS_FILE}}, nil},
+		{LintRule{Name: "bug-fix-key", Type: constant.LINT_RULE_FORMAT, Pattern: "[A-Z][A-Z0-9]+-[0-9]+",
+			Targets: []string{constant.LINT_TARGET_BUG_FIX_KEYS}}, []string{"carbon_5678"}},
+		{LintRule{Name: "max-length", Type: constant.LINT_RULE_MAX_LENGTH, MaxLength: 20,
+			Targets: []string{constant.LINT_TARGET_DESCRIPTION}}, []string{"47 characters"}},
+		{LintRule{Name: "restart", Type: constant.LINT_RULE_REQUIRED, Pattern: "(?i)restart",
+			Condition: constant.LINT_CONDITION_JARS_CHANGED, Targets: []string{constant.LINT_TARGET_INSTRUCTIONS}},
+			[]string{}},
+		{LintRule{Name: "unknown-condition", Type: constant.LINT_RULE_REQUIRED, Pattern: "(?i)restart",
+			Condition: "unknown", Targets: []string{constant.LINT_TARGET_INSTRUCTIONS}}, nil},
+		{LintRule{Name: "unknown-target", Type: constant.LINT_RULE_REQUIRED, Pattern: "(?i)restart",
+			Targets: []string{constant.README_FILE}}, nil},
+	}
+	for _, data := range testData {
+		violations, err := ApplyLintRules([]LintRule{data.rule}, lintContext)
+		if err != nil {
+			t.Fatalf("Test failed for %s, unexpected error: %v", data.rule.Name, err)
+		}
+		if data.expected == nil {
+			if len(violations) != 0 {
+				t.Errorf("Test failed for %s, unexpected violations: %v", data.rule.Name, violations)
+			}
+			continue
+		}
+		if len(violations) != 1 {
+			t.Errorf("Test failed for %s, expected 1 violation, actual: %v", data.rule.Name, violations)
+			continue
+		}
+		if violations[0].Severity != constant.LINT_SEVERITY_WARNING {
+			t.Errorf("Test failed for %s, expected: %s, actual: %s", data.rule.Name,
+				constant.LINT_SEVERITY_WARNING, violations[0].Severity)
+		}
+		if !reflect.DeepEqual(violations[0].Details, data.expected) {
+			t.Errorf("Test failed for %s, expected: %v, actual: %v", data.rule.Name, data.expected,
+				violations[0].Details)
+		}
+	}
+}
+
+func TestApplyInvalidLintRules(t *testing.T) {
+	rules := []LintRule{
+		{Name: "", Type: constant.LINT_RULE_FORBIDDEN, Pattern: "a"},
+		{Name: "type", Type: "unknown"},
+		{Name: "severity", Type: constant.LINT_RULE_FORBIDDEN, Pattern: "a", Severity: "fatal"},
+		{Name: "pattern", Type: constant.LINT_RULE_FORBIDDEN, Pattern: "("},
+		{Name: "max-length", Type: constant.LINT_RULE_MAX_LENGTH},
+	}
+	for _, rule := range rules {
+		if _, err := ApplyLintRules([]LintRule{rule}, NewLintContext(nil)); err == nil {
+			t.Errorf("Test failed, expected an error for the rule %v", rule)
+		}
+	}
+}
+
+func TestGetLintRules(t *testing.T) {
+	config := `
+- name: bug-fix-key
+  type: format
+  severity: error
+  targets: [bug_fix_keys]
+  pattern: "[A-Z]+-[0-9]+"
+- name: description-length
+  type: max_length
+  targets: [description]
+  max_length: 200
+`
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(config), &value); err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(constant.LINT_RULES, value)
+	defer viper.Set(constant.LINT_RULES, nil)
+
+	rules, err := GetLintRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Test failed, expected 2 rules, actual: %v", rules)
+	}
+	if rules[0].Severity != constant.LINT_SEVERITY_ERROR || rules[0].Pattern != "[A-Z]+-[0-9]+" ||
+		rules[0].Targets[0] != constant.LINT_TARGET_BUG_FIX_KEYS {
+		t.Errorf("Test failed, unexpected rule: %v", rules[0])
+	}
+	if rules[1].MaxLength != 200 {
+		t.Errorf("Test failed, expected: 200, actual: %d", rules[1].MaxLength)
+	}
+
+	violations, err := ApplyLintRules(rules, NewLintContext(getTestUpdateDescriptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ReportLintViolations(violations); err == nil {
+		t.Errorf("Test failed, expected an error as a rule with the error severity failed")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+// struct which is used to read and write checksums.yaml. Key of the Files map is the path of the file relative to the
+// update root directory.
+type ChecksumManifest struct {
+	Files map[string]FileChecksum `yaml:"files"`
+}
+
+// struct which is used to store the checksum and the size of a file in checksums.yaml
+type FileChecksum struct {
+	Sha256sum string `yaml:"sha256sum"`
+	Size      int64  `yaml:"size"`
+}
+
+// This function will create the checksum of the given file content.
+func NewFileChecksum(data []byte) FileChecksum {
+	checksum, _ := GetChecksumOfData(data, constant.HASH_ALGORITHM_SHA256)
+	return FileChecksum{
+		Sha256sum: checksum,
+		Size:      int64(len(data)),
+	}
+}
+
+// This function will generate the checksum manifest of all the files in the given update root directory. The manifest
+// file itself is not included.
+func GenerateChecksumManifest(updateRoot string) (*ChecksumManifest, error) {
+	manifest := ChecksumManifest{
+		Files: make(map[string]FileChecksum),
+	}
+	err := filepath.Walk(updateRoot, func(absolutePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fileInfo.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(updateRoot, absolutePath)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if relativePath == constant.CHECKSUMS_FILE {
+			return nil
+		}
+		checksum, err := GetChecksum(absolutePath, constant.HASH_ALGORITHM_SHA256)
+		if err != nil {
+			return err
+		}
+		manifest.Files[relativePath] = FileChecksum{
+			Sha256sum: checksum,
+			Size:      fileInfo.Size(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// This function will generate the checksum manifest of the given update root directory and write it to the
+// checksums.yaml in the same directory.
+func WriteChecksumManifest(updateRoot string) error {
+	manifest, err := GenerateChecksumManifest(updateRoot)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return WriteFileToDestination(data, filepath.Join(updateRoot, constant.CHECKSUMS_FILE))
+}
+
+// This function will compare the given checksums of the files in an update with the manifest. An error is returned
+// listing all the files which do not match, which are not listed in the manifest or which are missing in the update.
+func VerifyChecksumManifest(manifest *ChecksumManifest, actualChecksums map[string]FileChecksum) error {
+	problems := make([]string, 0)
+	for filePath, actualChecksum := range actualChecksums {
+		expectedChecksum, found := manifest.Files[filePath]
+		if !found {
+			problems = append(problems, fmt.Sprintf("'%s' is not listed in '%s'", filePath,
+				constant.CHECKSUMS_FILE))
+		} else if expectedChecksum != actualChecksum {
+			problems = append(problems, fmt.Sprintf("'%s' does not match the checksum in '%s'", filePath,
+				constant.CHECKSUMS_FILE))
+		}
+	}
+	for filePath := range manifest.Files {
+		if _, found := actualChecksums[filePath]; !found {
+			problems = append(problems, fmt.Sprintf("'%s' listed in '%s' is missing", filePath,
+				constant.CHECKSUMS_FILE))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	message := "Checksum verification of the update failed."
+	for _, problem := range problems {
+		message += "\n\t" + problem
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestChecksumManifest(t *testing.T) {
+	updateRoot, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating temp directory: %v", err)
+	}
+	defer os.RemoveAll(updateRoot)
+
+	files := map[string]string{
+		"LICENSE.txt":                       "license",
+		"carbon.home/repository/conf/a.xml": "<a/>",
+	}
+	for filePath, content := range files {
+		absolutePath := filepath.Join(updateRoot, filepath.FromSlash(filePath))
+		os.MkdirAll(filepath.Dir(absolutePath), 0755)
+		ioutil.WriteFile(absolutePath, []byte(content), 0644)
+	}
+	if err = WriteChecksumManifest(updateRoot); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	// The manifest should not list itself when regenerated
+	manifest, err := GenerateChecksumManifest(updateRoot)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if _, found := manifest.Files[constant.CHECKSUMS_FILE]; found || len(manifest.Files) != len(files) {
+		t.Errorf("Test failed, unexpected files in the manifest: %v", manifest.Files)
+	}
+
+	actualChecksums := make(map[string]FileChecksum)
+	for filePath, content := range files {
+		actualChecksums[filePath] = NewFileChecksum([]byte(content))
+	}
+	if err = VerifyChecksumManifest(manifest, actualChecksums); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+
+	// Modified file
+	actualChecksums["LICENSE.txt"] = NewFileChecksum([]byte("modified"))
+	err = VerifyChecksumManifest(manifest, actualChecksums)
+	if err == nil || !strings.Contains(err.Error(), "'LICENSE.txt' does not match") {
+		t.Errorf("Test failed, expected a checksum mismatch, actual: %v", err)
+	}
+
+	// Unlisted and missing files
+	delete(actualChecksums, "LICENSE.txt")
+	actualChecksums["carbon.home/unlisted.jar"] = NewFileChecksum([]byte("unlisted"))
+	err = VerifyChecksumManifest(manifest, actualChecksums)
+	if err == nil || !strings.Contains(err.Error(), "'carbon.home/unlisted.jar' is not listed") ||
+		!strings.Contains(err.Error(), "'LICENSE.txt' listed in 'checksums.yaml' is missing") {
+		t.Errorf("Test failed, expected unlisted and missing files, actual: %v", err)
+	}
+}
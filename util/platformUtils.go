@@ -0,0 +1,231 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// struct which is used to store a platform which updates can be created for. Deprecated platforms are not shown in the
+// selection menu, but updates of them are still accepted.
+type Platform struct {
+	Name       string `mapstructure:"name" yaml:"name" json:"name"`
+	Version    string `mapstructure:"version" yaml:"version" json:"version"`
+	SVNURL     string `mapstructure:"svn_url" yaml:"svn_url,omitempty" json:"svn_url,omitempty"`
+	Deprecated bool   `mapstructure:"deprecated" yaml:"deprecated,omitempty" json:"deprecated,omitempty"`
+}
+
+// struct which is used to look up the supported platforms
+type PlatformRegistry struct {
+	platforms []Platform
+}
+
+var platformRegistry *PlatformRegistry
+
+// This function returns the platform registry. Platforms are loaded from the platforms endpoint if the PLATFORMS_URL
+// is configured, or else from the config.yaml.
+func GetPlatformRegistry() *PlatformRegistry {
+	if platformRegistry == nil {
+		platformRegistry = NewPlatformRegistry(loadPlatforms())
+	}
+	return platformRegistry
+}
+
+// This function will create a new platform registry with the given platforms.
+func NewPlatformRegistry(platforms []Platform) *PlatformRegistry {
+	return &PlatformRegistry{platforms: platforms}
+}
+
+// This function will load the platforms. If the PLATFORMS_URL is configured, platforms are read from the cache in
+// $WUMUC_HOME which is refreshed from the endpoint once a day. The configured platforms are used if neither the
+// endpoint nor the cache is available.
+func loadPlatforms() []Platform {
+	platformsURL := viper.GetString(constant.PLATFORMS_URL)
+	if len(platformsURL) > 0 {
+		cacheFilePath := filepath.Join(viper.GetString(constant.WUM_UC_HOME), constant.WUMUC_CACHE_DIRECTORY,
+			constant.PLATFORMS_CACHE_FILE)
+		platforms, err := GetPlatformsFromServer(platformsURL, cacheFilePath, nil)
+		if err == nil {
+			return platforms
+		}
+		logger.Debug(fmt.Sprintf("Error occurred while getting the platforms from '%s': %v", platformsURL, err))
+		PrintWarning(fmt.Sprintf("Unable to get the platforms from '%s'. Using the configured platforms.",
+			platformsURL))
+	}
+	platforms, err := GetConfiguredPlatforms()
+	if err != nil {
+		HandleErrorAndExit(err)
+	}
+	return platforms
+}
+
+// This function returns the platforms configured in the config.yaml. Platforms configured using the legacy
+// PLATFORM_VERSIONS map are added if a platform with the same version is not configured.
+func GetConfiguredPlatforms() ([]Platform, error) {
+	platforms := make([]Platform, 0)
+	if err := viper.UnmarshalKey(constant.PLATFORMS, &platforms); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid %s found in the config. %v", constant.PLATFORMS, err))
+	}
+	legacyPlatforms := viper.GetStringMapString(constant.PLATFORM_VERSIONS)
+	for _, platform := range platforms {
+		delete(legacyPlatforms, platform.Version)
+	}
+	versions := make([]string, 0)
+	for version := range legacyPlatforms {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	for _, version := range versions {
+		platforms = append(platforms, Platform{Name: legacyPlatforms[version], Version: version})
+	}
+	return platforms, nil
+}
+
+// This function will get the platforms from the given endpoint. The response is cached in the given location and the
+// cached platforms are returned if the cache is not older than one day or if the endpoint is not reachable.
+func GetPlatformsFromServer(url, cacheFilePath string, client *http.Client) ([]Platform, error) {
+	cacheInfo, err := os.Stat(cacheFilePath)
+	isCacheFound := err == nil
+	if isCacheFound && time.Since(cacheInfo.ModTime()).Hours() <= constant.PLATFORMS_CACHE_DURATION_IN_HOURS {
+		if platforms, err := readPlatformsFile(cacheFilePath); err == nil {
+			logger.Debug(fmt.Sprintf("Platforms read from the cache '%s'", cacheFilePath))
+			return platforms, nil
+		}
+	}
+	data, err := requestPlatforms(url, client)
+	if err == nil {
+		platforms := make([]Platform, 0)
+		if err = json.Unmarshal(data, &platforms); err == nil {
+			if err = os.MkdirAll(filepath.Dir(cacheFilePath), 0700); err == nil {
+				err = ioutil.WriteFile(cacheFilePath, data, 0600)
+			}
+			if err != nil {
+				logger.Debug(fmt.Sprintf("Error occurred while caching the platforms in '%s': %v", cacheFilePath,
+					err))
+			}
+			return platforms, nil
+		}
+	}
+	if isCacheFound {
+		logger.Debug(fmt.Sprintf("Error occurred while refreshing the platforms. Using the cache '%s': %v",
+			cacheFilePath, err))
+		return readPlatformsFile(cacheFilePath)
+	}
+	return nil, err
+}
+
+// This function will send a GET request to the platforms endpoint and return the response body. Shared HTTP client is
+// used if a client is not given.
+func requestPlatforms(url string, client *http.Client) ([]byte, error) {
+	httpClient := GetHTTPClient()
+	if client != nil {
+		httpClient = &HTTPClient{Client: client}
+	}
+	logger.Debug(fmt.Sprintf("Requesting %s", url))
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set(constant.HEADER_ACCEPT, constant.HEADER_VALUE_APPLICATION_JSON)
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, newHTTPError(url, response)
+	}
+	defer response.Body.Close()
+	return ioutil.ReadAll(response.Body)
+}
+
+// This function will read the platforms stored in the given json file.
+func readPlatformsFile(location string) ([]Platform, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+	platforms := make([]Platform, 0)
+	if err = json.Unmarshal(data, &platforms); err != nil {
+		return nil, err
+	}
+	return platforms, nil
+}
+
+// This function returns the platforms which are not deprecated. These are shown in the selection menu.
+func (registry *PlatformRegistry) GetSelectablePlatforms() []Platform {
+	platforms := make([]Platform, 0)
+	for _, platform := range registry.platforms {
+		if !platform.Deprecated {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// This function returns the platform with the given platform version.
+func (registry *PlatformRegistry) GetPlatformByVersion(version string) (Platform, bool) {
+	for _, platform := range registry.platforms {
+		if platform.Version == version {
+			return platform, true
+		}
+	}
+	return Platform{}, false
+}
+
+// This function returns the platform with the given platform name.
+func (registry *PlatformRegistry) GetPlatformByName(name string) (Platform, bool) {
+	for _, platform := range registry.platforms {
+		if platform.Name == name {
+			return platform, true
+		}
+	}
+	return Platform{}, false
+}
+
+// This function will validate the given platform name against the given platform version. Unknown platform versions
+// are accepted as the registry might not be up to date.
+func (registry *PlatformRegistry) ValidatePlatform(name, version string) error {
+	platform, found := registry.GetPlatformByVersion(version)
+	if !found {
+		logger.Debug(fmt.Sprintf("Platform version '%s' not found in the platform registry", version))
+		return nil
+	}
+	if platform.Name != name {
+		return errors.New(fmt.Sprintf("'platform_name' is not valid. Platform name of the platform version '%s' "+
+			"should be '%s', but found '%s'.", version, platform.Name, name))
+	}
+	return nil
+}
+
+// This function returns the SVN URL of the updates of the given platform. If the SVN URL is not configured for the
+// platform, the default SVN location is used.
+func (registry *PlatformRegistry) GetSVNURL(name string) string {
+	if platform, found := registry.GetPlatformByName(name); found && len(platform.SVNURL) > 0 {
+		return strings.TrimSuffix(platform.SVNURL, "/")
+	}
+	return GetWUMUCConfigs().GetSVNRepositoryURL() + "/" + name + "/" + constant.SVN_UPDATES
+}
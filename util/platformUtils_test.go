@@ -0,0 +1,134 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestPlatformRegistry(t *testing.T) {
+	registry := NewPlatformRegistry([]Platform{
+		{Name: "perlis", Version: "4.3.0", Deprecated: true},
+		{Name: "wilkes", Version: "4.4.0", SVNURL: "https://svn.example.com/wilkes/updates/"},
+		{Name: "hamming", Version: "5.0.0"},
+	})
+
+	selectable := registry.GetSelectablePlatforms()
+	if len(selectable) != 2 || selectable[0].Name != "wilkes" || selectable[1].Name != "hamming" {
+		t.Errorf("Test failed, unexpected selectable platforms: %v", selectable)
+	}
+	if platform, found := registry.GetPlatformByVersion("4.3.0"); !found || platform.Name != "perlis" {
+		t.Errorf("Test failed, deprecated platform not found: %v", platform)
+	}
+	if err := registry.ValidatePlatform("wilkes", "4.4.0"); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+	if err := registry.ValidatePlatform("hamming", "4.4.0"); err == nil {
+		t.Error("Test failed, mismatching platform name and version accepted")
+	}
+	if err := registry.ValidatePlatform("unknown", "6.0.0"); err != nil {
+		t.Errorf("Test failed, unknown platform version rejected: %v", err)
+	}
+	if url := registry.GetSVNURL("wilkes"); url != "https://svn.example.com/wilkes/updates" {
+		t.Errorf("Test failed, unexpected SVN URL: %s", url)
+	}
+	if url := registry.GetSVNURL("hamming"); url != constant.SVN_UPDATE_REPO+"/hamming/"+constant.SVN_UPDATES {
+		t.Errorf("Test failed, unexpected SVN URL: %s", url)
+	}
+}
+
+func TestGetConfiguredPlatforms(t *testing.T) {
+	viper.Set(constant.PLATFORMS, []map[string]interface{}{
+		{"name": "wilkes", "version": "4.4.0"},
+		{"name": "hamming", "version": "5.0.0", "deprecated": true},
+	})
+	viper.Set(constant.PLATFORM_VERSIONS, map[string]string{"4.4.0": "other", "4.2.0": "turing"})
+	defer viper.Reset()
+
+	platforms, err := GetConfiguredPlatforms()
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	expected := []Platform{
+		{Name: "wilkes", Version: "4.4.0"},
+		{Name: "hamming", Version: "5.0.0", Deprecated: true},
+		{Name: "turing", Version: "4.2.0"},
+	}
+	if len(platforms) != len(expected) {
+		t.Fatalf("Test failed, expected %v, found %v", expected, platforms)
+	}
+	for i := range expected {
+		if platforms[i] != expected[i] {
+			t.Errorf("Test failed, expected %v, found %v", expected[i], platforms[i])
+		}
+	}
+}
+
+func TestGetPlatformsFromServer(t *testing.T) {
+	requestCount := 0
+	isAvailable := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if !isAvailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"name": "wilkes", "version": "4.4.0"}, {"name": "hamming", "version": "5.0.0"}]`))
+	}))
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	cacheFilePath := filepath.Join(tempDir, "cache", "platforms.json")
+
+	platforms, err := GetPlatformsFromServer(server.URL, cacheFilePath, nil)
+	if err != nil || len(platforms) != 2 || requestCount != 1 {
+		t.Fatalf("Test failed, platforms: %v, requests: %d, error: %v", platforms, requestCount, err)
+	}
+
+	// Fresh cache should be used without requesting the server
+	platforms, err = GetPlatformsFromServer(server.URL, cacheFilePath, nil)
+	if err != nil || len(platforms) != 2 || requestCount != 1 {
+		t.Fatalf("Test failed, platforms: %v, requests: %d, error: %v", platforms, requestCount, err)
+	}
+
+	// Expired cache should be used if the server is not available
+	expired := time.Now().Add(-(constant.PLATFORMS_CACHE_DURATION_IN_HOURS + 1) * time.Hour)
+	if err = os.Chtimes(cacheFilePath, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	isAvailable = false
+	platforms, err = GetPlatformsFromServer(server.URL, cacheFilePath, nil)
+	if err != nil || len(platforms) != 2 || requestCount != 2 {
+		t.Fatalf("Test failed, platforms: %v, requests: %d, error: %v", platforms, requestCount, err)
+	}
+
+	// Error should be returned if neither the server nor the cache is available
+	if _, err = GetPlatformsFromServer(server.URL, filepath.Join(tempDir, "missing.json"), nil); err == nil {
+		t.Error("Test failed, error expected when the server and the cache are not available")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// Named set of endpoints and credentials in the config.yaml, which can be used instead of the top level values (ie.
+// for a staging environment). Values which are not given in the profile are taken from the top level values, except
+// the credentials. Each profile has its own credentials in the credential store.
+type WUMUCProfile struct {
+	ServerURL  string `yaml:"serverurl,omitempty"`
+	TokenURL   string `yaml:"tokenurl,omitempty"`
+	VersionURL string `yaml:"versionurl,omitempty"`
+	RevokeURL  string `yaml:"revokeurl,omitempty"`
+	AppKey     string `yaml:"appkey,omitempty"`
+	Username   string `yaml:"username,omitempty"`
+	ClientID   string `yaml:"clientid,omitempty"`
+	// Root of the SVN repository in which the updates are committed
+	SVNURL string `yaml:"svnurl,omitempty"`
+	// URLs of the resource files copied to the updates
+	LicenseURL          string `yaml:"licenseurl,omitempty"`
+	NotAContributionURL string `yaml:"notacontributionurl,omitempty"`
+}
+
+// This function returns the name of the selected profile. Empty string is returned if the top level values are used.
+func (wumucConfig *WUMUCConfig) GetProfile() string {
+	return wumucConfig.profile
+}
+
+// This function returns the names of the profiles in the config.yaml in the sorted order.
+func (wumucConfig *WUMUCConfig) GetProfileNames() []string {
+	names := make([]string, 0, len(wumucConfig.Profiles))
+	for name := range wumucConfig.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// This function will use the values of the given profile instead of the top level values. Credentials of the top level
+// are removed, as they should be loaded from the credential store of the profile.
+func (wumucConfig *WUMUCConfig) selectProfile(name string) error {
+	if !regexp.MustCompile(constant.PROFILE_NAME_REGEX).MatchString(name) {
+		return errors.New(fmt.Sprintf("invalid profile name '%s'. It should match the regex '%s'", name,
+			constant.PROFILE_NAME_REGEX))
+	}
+	profile, found := wumucConfig.Profiles[name]
+	if !found || profile == nil {
+		names := wumucConfig.GetProfileNames()
+		if len(names) == 0 {
+			return errors.New(fmt.Sprintf("profile '%s' not found. No profiles are configured in the config.yaml",
+				name))
+		}
+		return errors.New(fmt.Sprintf("profile '%s' not found. Available profiles: %s", name,
+			strings.Join(names, ", ")))
+	}
+	overrides := []struct {
+		value  string
+		target *string
+	}{
+		{profile.ServerURL, &wumucConfig.ServerURL},
+		{profile.TokenURL, &wumucConfig.TokenURL},
+		{profile.VersionURL, &wumucConfig.VersionURL},
+		{profile.RevokeURL, &wumucConfig.RevokeURL},
+		{profile.AppKey, &wumucConfig.AppKey},
+		{profile.SVNURL, &wumucConfig.SVNURL},
+		{profile.LicenseURL, &wumucConfig.LicenseURL},
+		{profile.NotAContributionURL, &wumucConfig.NotAContributionURL},
+	}
+	for _, override := range overrides {
+		if len(override.value) > 0 {
+			*override.target = override.value
+		}
+	}
+	// Token API of a profile is not derived from the top level server URL
+	if len(profile.ServerURL) > 0 && len(profile.TokenURL) == 0 {
+		wumucConfig.TokenURL = strings.TrimSuffix(profile.ServerURL, "/") + "/" + constant.TOKEN_API_CONTEXT
+	}
+	wumucConfig.Username = profile.Username
+	wumucConfig.ClientID = profile.ClientID
+	wumucConfig.setCredentials(&Credentials{})
+	wumucConfig.profile = name
+	return nil
+}
+
+// This function will store the values of the selected profile which are changed by wum-uc (the username and the client
+// id) in the given configuration read from the config.yaml.
+func (wumucConfig *WUMUCConfig) updateProfile(fileConfig *WUMUCConfig) error {
+	profile, found := fileConfig.Profiles[wumucConfig.profile]
+	if !found || profile == nil {
+		return errors.New(fmt.Sprintf("profile '%s' not found in the config.yaml", wumucConfig.profile))
+	}
+	profile.Username = wumucConfig.Username
+	profile.ClientID = wumucConfig.ClientID
+	return nil
+}
+
+// This function returns the name of the credentials file of the given profile.
+func getCredentialsFileName(profile string) string {
+	if len(profile) == 0 {
+		return constant.WUMUC_CREDENTIALS_FILE
+	}
+	return constant.WUMUC_CREDENTIALS_FILE + "-" + profile
+}
+
+// This function returns the URL of the given resource file (ie. LICENSE_URL). Environment variable takes precedence
+// over the URL in the config.yaml. False is returned if the URL is not configured.
+func LookupResourceFileURL(urlName string) (string, bool) {
+	if url, exists := os.LookupEnv(urlName); exists {
+		return url, true
+	}
+	var url string
+	switch urlName {
+	case constant.LICENSE_URL:
+		url = GetWUMUCConfigs().LicenseURL
+	case constant.NOT_A_CONTRIBUTION_URL:
+		url = GetWUMUCConfigs().NotAContributionURL
+	}
+	return url, len(url) > 0
+}
+
+// This function returns the root of the SVN repository in which the updates are committed.
+func (wumucConfig *WUMUCConfig) GetSVNRepositoryURL() string {
+	if len(wumucConfig.SVNURL) > 0 {
+		return strings.TrimSuffix(wumucConfig.SVNURL, "/") + "/"
+	}
+	return constant.SVN_UPDATE_REPO
+}
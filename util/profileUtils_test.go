@@ -0,0 +1,125 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+const testProfilesConfig = "serverurl: https://wum.example.com\ntokenurl: https://wum.example.com/token\n" +
+	"versionurl: https://wum.example.com/version\nappkey: key\nusername: user@wso2.com\n" +
+	"profiles:\n" +
+	"  staging:\n" +
+	"    serverurl: https://staging.example.com/\n" +
+	"    svnurl: https://svn.staging.example.com/updates/\n" +
+	"    licenseurl: https://staging.example.com/LICENSE.txt\n" +
+	"    username: staging@wso2.com\n" +
+	"  empty: {}\n"
+
+func TestSelectProfile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	if err = ioutil.WriteFile(configFile, []byte(testProfilesConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _, err := readConfigFile(configFile, "staging")
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if config.GetProfile() != "staging" || config.ServerURL != "https://staging.example.com/" ||
+		config.TokenURL != "https://staging.example.com/"+constant.TOKEN_API_CONTEXT ||
+		config.VersionURL != "https://wum.example.com/version" || config.Username != "staging@wso2.com" {
+		t.Errorf("Test failed, profile values not used: %v", config)
+	}
+	if url := config.GetSVNRepositoryURL(); url != "https://svn.staging.example.com/updates/" {
+		t.Errorf("Test failed, unexpected SVN repository URL: %s", url)
+	}
+	if config.LicenseURL != "https://staging.example.com/LICENSE.txt" || config.NotAContributionURL != "" {
+		t.Errorf("Test failed, unexpected resource file URLs: %v", config)
+	}
+
+	config, _, err = readConfigFile(configFile, "empty")
+	if err != nil || config.ServerURL != "https://wum.example.com" || len(config.Username) != 0 ||
+		config.GetSVNRepositoryURL() != constant.SVN_UPDATE_REPO {
+		t.Errorf("Test failed, unexpected values of an empty profile: %v, error: %v", config, err)
+	}
+
+	for _, name := range []string{"missing", "../staging"} {
+		if _, _, err = readConfigFile(configFile, name); err == nil {
+			t.Errorf("Test failed, expected an error for the profile '%s'", name)
+		}
+	}
+	if _, _, err = readConfigFile(configFile, "missing"); err == nil ||
+		!strings.Contains(err.Error(), "empty, staging") {
+		t.Errorf("Test failed, available profiles not listed in the error: %v", err)
+	}
+}
+
+func TestProfileCredentials(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer setEnv(constant.WUMUC_CREDENTIALS_PASSPHRASE, "")()
+	configFile := filepath.Join(tempDir, constant.WUMUC_CONFIG_FILE)
+	if err = ioutil.WriteFile(configFile, []byte(testProfilesConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _, err := readConfigFile(configFile, "staging")
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	config.Username = "another@wso2.com"
+	config.setCredentials(&testCredentials)
+	if err = WriteConfigFile(config, configFile); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_FILE+"-staging")); err != nil {
+		t.Errorf("Test failed, credentials file of the profile not created: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(tempDir, constant.WUMUC_CREDENTIALS_FILE)); err == nil {
+		t.Error("Test failed, credentials of the profile written to the default credentials file")
+	}
+
+	// Top level values should be kept as they are
+	defaultConfig, _, err := readConfigFile(configFile, "")
+	if err != nil || defaultConfig.ServerURL != "https://wum.example.com" ||
+		defaultConfig.Username != "user@wso2.com" || len(defaultConfig.AccessToken) != 0 {
+		t.Errorf("Test failed, top level values changed: %v, error: %v", defaultConfig, err)
+	}
+	if profile := defaultConfig.Profiles["staging"]; profile == nil || profile.Username != "another@wso2.com" ||
+		profile.ServerURL != "https://staging.example.com/" {
+		t.Errorf("Test failed, unexpected profile values: %v", profile)
+	}
+	config, _, err = readConfigFile(configFile, "staging")
+	if err != nil || config.AccessToken != testCredentials.AccessToken {
+		t.Errorf("Test failed, credentials of the profile not loaded: %v, error: %v", config, err)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+	"gopkg.in/yaml.v2"
+)
+
+// Project level configuration found in the .wum-uc.yaml of an update directory and its parents. Other keys in the
+// file (ie. RESOURCE_FILES) are merged over the user config.
+type ProjectConfig struct {
+	// Prefix of the update name. constant.UPDATE_NAME_PREFIX is used if this is empty
+	UpdateNamePrefix string `yaml:"update_name_prefix,omitempty"`
+	// Platform and the update number of the update. User is not asked for these values if they are pinned
+	PlatformName    string `yaml:"platform_name,omitempty"`
+	PlatformVersion string `yaml:"platform_version,omitempty"`
+	UpdateNumber    string `yaml:"update_number,omitempty"`
+	// Files removed by the update, relative to the PRODUCT_HOME
+	RemovedFiles []string `yaml:"removed_files,omitempty"`
+	// Directories relative to the PRODUCT_HOME to which the files and directories in the root of the update
+	// directory are copied, without looking for matches in the distribution
+	Destinations map[string][]string `yaml:"destinations,omitempty"`
+	// Paths of the loaded files, starting from the outermost directory
+	filePaths []string
+}
+
+var projectConfig ProjectConfig
+
+// This function will discover the .wum-uc.yaml files in the given directory and its parents and load them. Files in
+// inner directories take precedence over the files in outer directories. Keys of the user config in the files are
+// merged over the user config.
+func LoadProjectConfig(directory string) error {
+	projectConfig = ProjectConfig{}
+	filePaths, err := findProjectConfigFiles(directory)
+	if err != nil {
+		return err
+	}
+	for _, filePath := range filePaths {
+		logger.Debug(fmt.Sprintf("Loading the project configuration in %s", filePath))
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		if err = yaml.Unmarshal(data, &projectConfig); err != nil {
+			return errors.New(fmt.Sprintf("invalid project configuration found in '%s'. %v", filePath, err))
+		}
+		settings := make(map[string]interface{})
+		if err = yaml.Unmarshal(data, &settings); err != nil {
+			return errors.New(fmt.Sprintf("invalid project configuration found in '%s'. %v", filePath, err))
+		}
+		if err = mergeProjectSettings(settings, filePath); err != nil {
+			return err
+		}
+		projectConfig.filePaths = append(projectConfig.filePaths, filePath)
+	}
+	if err = projectConfig.validate(); err != nil {
+		return errors.New(fmt.Sprintf("invalid project configuration found in %v. %v", filePaths, err))
+	}
+	// Platforms might be changed by the project configuration
+	platformRegistry = nil
+	return nil
+}
+
+// Returns the loaded project configuration.
+func GetProjectConfig() *ProjectConfig {
+	return &projectConfig
+}
+
+// This function returns the paths of the loaded .wum-uc.yaml files.
+func (projectConfig *ProjectConfig) GetFilePaths() []string {
+	return projectConfig.filePaths
+}
+
+// This function returns the prefix of the update name.
+func (projectConfig *ProjectConfig) GetUpdateNamePrefix() string {
+	if len(projectConfig.UpdateNamePrefix) > 0 {
+		return projectConfig.UpdateNamePrefix
+	}
+	return constant.UPDATE_NAME_PREFIX
+}
+
+// This function returns the regex which should be matched by the file names of the updates.
+func (projectConfig *ProjectConfig) GetFileNameRegex() string {
+	return strings.Replace(constant.FILENAME_REGEX, constant.UPDATE_NAME_PREFIX,
+		regexp.QuoteMeta(projectConfig.GetUpdateNamePrefix()), 1)
+}
+
+// This function returns the directories to which the given file or directory in the root of the update directory
+// should be copied. False is returned if the destinations are not pinned.
+func (projectConfig *ProjectConfig) GetDestinations(name string) ([]string, bool) {
+	destinations, found := projectConfig.Destinations[name]
+	return destinations, found && len(destinations) > 0
+}
+
+// This function returns the .wum-uc.yaml files in the given directory and its parents, starting from the outermost
+// directory.
+func findProjectConfigFiles(directory string) ([]string, error) {
+	currentDirectory, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+	filePaths := make([]string, 0)
+	for {
+		filePath := filepath.Join(currentDirectory, constant.PROJECT_CONFIG_FILE)
+		exists, err := IsFileExists(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			filePaths = append([]string{filePath}, filePaths...)
+		}
+		parentDirectory := filepath.Dir(currentDirectory)
+		if parentDirectory == currentDirectory {
+			return filePaths, nil
+		}
+		currentDirectory = parentDirectory
+	}
+}
+
+// This function will merge the keys of the user config in the given project configuration over the user config. Only
+// the keys which affect the created update can be overridden, so that an update directory cannot change the servers
+// or the credentials.
+func mergeProjectSettings(settings map[string]interface{}, filePath string) error {
+	projectKeys := getYAMLKeys(&ProjectConfig{})
+	userSettings := make(map[string]interface{})
+	for name, value := range settings {
+		if IsStringIsInSlice(name, projectKeys) {
+			continue
+		}
+		keys, err := getProjectSettingKeys(name, value)
+		if err != nil {
+			return errors.New(fmt.Sprintf("invalid project configuration found in '%s'. %v", filePath, err))
+		}
+		for _, key := range keys {
+			if !key.IsProjectKey {
+				return errors.New(fmt.Sprintf("'%s' cannot be set in '%s'. It can only be set in the user config",
+					key.Name, filePath))
+			}
+		}
+		userSettings[name] = value
+	}
+	if len(userSettings) == 0 {
+		return nil
+	}
+	if err := viper.MergeConfigMap(userSettings); err != nil {
+		return errors.New(fmt.Sprintf("error occurred while merging '%s'. %v", filePath, err))
+	}
+	// Values are validated after merging, as structured values are validated using the merged configuration
+	for name, value := range userSettings {
+		keys, _ := getProjectSettingKeys(name, value)
+		for _, key := range keys {
+			if err := key.Validate(viper.Get(key.Name)); err != nil {
+				return errors.New(fmt.Sprintf("invalid project configuration found in '%s'. %v", filePath, err))
+			}
+		}
+	}
+	return nil
+}
+
+// This function returns the keys of the user config which are set by the given value of the project configuration.
+// Nested values (ie. RESOURCE_FILES) might set multiple keys.
+func getProjectSettingKeys(name string, value interface{}) ([]*ConfigKey, error) {
+	if key, err := GetConfigKey(name); err == nil {
+		return []*ConfigKey{key}, nil
+	}
+	children, isMap := value.(map[interface{}]interface{})
+	if !isMap {
+		return nil, errors.New(fmt.Sprintf("unknown key '%s'", name))
+	}
+	keys := make([]*ConfigKey, 0)
+	for childName, childValue := range children {
+		childKeys, err := getProjectSettingKeys(name+"."+fmt.Sprint(childName), childValue)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, childKeys...)
+	}
+	return keys, nil
+}
+
+// This function will validate the values of the project configuration.
+func (projectConfig *ProjectConfig) validate() error {
+	if len(projectConfig.UpdateNamePrefix) > 0 &&
+		!regexp.MustCompile(constant.UPDATE_NAME_PREFIX_REGEX).MatchString(projectConfig.UpdateNamePrefix) {
+		return errors.New(fmt.Sprintf("update_name_prefix '%s' is not valid. It should match '%s'",
+			projectConfig.UpdateNamePrefix, constant.UPDATE_NAME_PREFIX_REGEX))
+	}
+	if len(projectConfig.UpdateNumber) > 0 && !ValidateUpdateNumber(projectConfig.UpdateNumber) {
+		return errors.New(fmt.Sprintf("update_number '%s' is not valid. It should match '%s'",
+			projectConfig.UpdateNumber, constant.UPDATE_NUMBER_REGEX))
+	}
+	if len(projectConfig.PlatformVersion) > 0 && !ValidatePlatformVersion(projectConfig.PlatformVersion) {
+		return errors.New(fmt.Sprintf("platform_version '%s' is not valid. It should match '%s'",
+			projectConfig.PlatformVersion, constant.KERNEL_VERSION_REGEX))
+	}
+	if len(projectConfig.PlatformName) > 0 && len(projectConfig.PlatformVersion) == 0 {
+		return errors.New("platform_version should be given along with the platform_name")
+	}
+	for _, removedFile := range projectConfig.RemovedFiles {
+		if !isRelativeProductPath(removedFile) {
+			return errors.New(fmt.Sprintf("removed file '%s' is not a valid path relative to the PRODUCT_HOME",
+				removedFile))
+		}
+	}
+	names := make([]string, 0, len(projectConfig.Destinations))
+	for name := range projectConfig.Destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.Contains(name, "/") || name == constant.PROJECT_CONFIG_FILE {
+			return errors.New(fmt.Sprintf("destinations can only be given for the files and directories in the "+
+				"root of the update directory, found '%s'", name))
+		}
+		for _, destination := range projectConfig.Destinations[name] {
+			if len(destination) > 0 && !isRelativeProductPath(destination) {
+				return errors.New(fmt.Sprintf("destination '%s' of '%s' is not a valid path relative to the "+
+					"PRODUCT_HOME", destination, name))
+			}
+		}
+	}
+	return nil
+}
+
+// This function checks whether the given path is a path inside the PRODUCT_HOME.
+func isRelativeProductPath(productPath string) bool {
+	cleanPath := path.Clean(filepath.ToSlash(productPath))
+	return len(productPath) > 0 && !path.IsAbs(cleanPath) && !filepath.IsAbs(productPath) && cleanPath != ".." &&
+		!strings.HasPrefix(cleanPath, "../")
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer func() { projectConfig = ProjectConfig{} }()
+	// User config
+	viper.MergeConfigMap(map[string]interface{}{
+		constant.HASH_ALGORITHM: "md5",
+		"resource_files":        map[string]interface{}{"mandatory": []string{"LICENSE.txt"}},
+	})
+
+	updateDirectory := filepath.Join(tempDir, "updates", "0001")
+	if err = os.MkdirAll(updateDirectory, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(tempDir, constant.PROJECT_CONFIG_FILE), []byte(
+		"update_name_prefix: WSO2-IS-UPDATE\nplatform_version: 4.4.0\nHASH_ALGORITHM: sha256\n"),
+		0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(updateDirectory, constant.PROJECT_CONFIG_FILE), []byte(
+		"update_number: \"0001\"\nremoved_files:\n- lib/old.jar\ndestinations:\n  patch.jar:\n"+
+			"  - repository/components/plugins\nDETERMINISTIC_ZIP: true\n"),
+		0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = LoadProjectConfig(updateDirectory); err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	projectConfig := GetProjectConfig()
+	if len(projectConfig.GetFilePaths()) != 2 ||
+		projectConfig.GetFilePaths()[0] != filepath.Join(tempDir, constant.PROJECT_CONFIG_FILE) {
+		t.Errorf("Test failed, unexpected files: %v", projectConfig.GetFilePaths())
+	}
+	if projectConfig.GetUpdateNamePrefix() != "WSO2-IS-UPDATE" || projectConfig.PlatformVersion != "4.4.0" ||
+		projectConfig.UpdateNumber != "0001" ||
+		!reflect.DeepEqual(projectConfig.RemovedFiles, []string{"lib/old.jar"}) {
+		t.Errorf("Test failed, unexpected project configuration: %v", projectConfig)
+	}
+	if destinations, pinned := projectConfig.GetDestinations("patch.jar"); !pinned ||
+		!reflect.DeepEqual(destinations, []string{"repository/components/plugins"}) {
+		t.Errorf("Test failed, unexpected destinations: %v", destinations)
+	}
+	if _, pinned := projectConfig.GetDestinations("other.jar"); pinned {
+		t.Error("Test failed, destinations found for a file which is not pinned")
+	}
+
+	// Keys of the user config should be merged over the user config
+	if viper.GetString(constant.HASH_ALGORITHM) != "sha256" ||
+		!reflect.DeepEqual(viper.GetStringSlice(constant.RESOURCE_FILES_MANDATORY), []string{"LICENSE.txt"}) ||
+		!viper.GetBool(constant.DETERMINISTIC_ZIP) {
+		t.Errorf("Test failed, project configuration not merged: %v", viper.AllSettings())
+	}
+
+	regex := regexp.MustCompile(projectConfig.GetFileNameRegex())
+	if !regex.MatchString("WSO2-IS-UPDATE-4.4.0-0001.zip") || regex.MatchString("WSO2-CARBON-UPDATE-4.4.0-0001.zip") {
+		t.Errorf("Test failed, unexpected file name regex: %s", projectConfig.GetFileNameRegex())
+	}
+}
+
+func TestLoadInvalidProjectConfig(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer viper.Reset()
+	defer func() { projectConfig = ProjectConfig{} }()
+
+	invalidConfigs := map[string]string{
+		"serverurl: https://wum.example.com\n":                "can only be set in the user config",
+		"credentials:\n  helper: /tmp/helper\n":               "can only be set in the user config",
+		"PLATFORMS:\n- name: wilkes\n  version: 4.4.0\n":      "can only be set in the user config",
+		"CHECK_MD5_DISABLED: true\n":                          "can only be set in the user config",
+		"MAX_CLASS_VERSIONS:\n  4.4.0: 55\n":                  "can only be set in the user config",
+		"LINT_RULES: []\n":                                    "can only be set in the user config",
+		"RESOURCE_FILES:\n  OPTIONAL:\n  - secrets.txt\n":     "can only be set in the user config",
+		"unknown: value\n":                                    "unknown key",
+		"HASH_ALGORITHM: sha1\n":                              constant.HASH_ALGORITHM,
+		"update_number: \"1\"\n":                              "update_number",
+		"update_name_prefix: ../UPDATE\n":                     "update_name_prefix",
+		"platform_name: wilkes\n":                             "platform_version",
+		"removed_files:\n- ../lib/old.jar\n":                  "removed file",
+		"destinations:\n  patch.jar:\n  - /repository/lib\n":  "destination",
+		"destinations:\n  lib/patch.jar:\n  - repository/lib": "destinations",
+	}
+	for config, expected := range invalidConfigs {
+		if err = ioutil.WriteFile(filepath.Join(tempDir, constant.PROJECT_CONFIG_FILE), []byte(config),
+			0600); err != nil {
+			t.Fatal(err)
+		}
+		if err = LoadProjectConfig(tempDir); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Test failed for '%s', expected an error containing '%s', found: %v", config, expected, err)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wso2/update-creator-tool/constant"
+)
+
+// struct which is used to store a possible secret found in a file of an update
+type SecretFinding struct {
+	FilePath   string
+	LineNumber int
+	Rule       string
+}
+
+// This function returns a human readable description of the finding.
+func (finding SecretFinding) String() string {
+	if finding.LineNumber > 0 {
+		return fmt.Sprintf("%s:%d (%s)", finding.FilePath, finding.LineNumber, finding.Rule)
+	}
+	return fmt.Sprintf("%s (%s)", finding.FilePath, finding.Rule)
+}
+
+// struct which is used to store an entry of the secrets allowlist. If the rule is empty, all the rules are allowed for
+// the matching files.
+type secretsAllowlistEntry struct {
+	pathPattern string
+	rule        string
+}
+
+// struct which is used to store the known false positives which should not be reported by the secret scanner
+type SecretsAllowlist struct {
+	entries []secretsAllowlistEntry
+}
+
+var (
+	privateKeyPattern    = regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|ENCRYPTED|PGP) )?PRIVATE KEY( BLOCK)?-----`)
+	awsAccessKeyPattern  = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	awsSecretKeyPattern  = regexp.MustCompile(`(?i)aws_?secret_?(access_?)?key\s*[=:]\s*["']?[A-Za-z0-9/+=]{40}`)
+	passwordPattern      = regexp.MustCompile(`(?i)[\w.-]*(password|passwd)[\w.-]*\s*[=:]\s*("([^"]*)"|'([^']*)'|([^\s#;,]*))`)
+	xmlPasswordPattern   = regexp.MustCompile(`(?i)<([\w:.-]*password)>([^<]*)</`)
+	jksMagicNumbers      = [][]byte{{0xFE, 0xED, 0xFE, 0xED}, {0xCE, 0xCE, 0xCE, 0xCE}}
+	passwordValueIndexes = []int{3, 4, 5}
+	// DER encoding of the version (3) of a PKCS12 PFX and the prefix of the PKCS7 content type OID of its authSafe
+	pkcs12Version   = []byte{0x02, 0x01, 0x03}
+	pkcs7OIDPrefix  = []byte{0x06, 0x09, 0x2A, 0x86, 0x48, 0x86, 0xF7, 0x0D, 0x01, 0x07}
+	asn1SequenceTag = byte(0x30)
+)
+
+// Number of bytes read from the files which are not scanned for secrets, to check whether they are keystores.
+const keystoreHeaderSize = 32
+
+// This function will scan all the files in the carbon.home directory of the given update root directory for secrets.
+// File paths of the findings are relative to the update root.
+func ScanDirectoryForSecrets(updateRoot string) ([]SecretFinding, error) {
+	findings := make([]SecretFinding, 0)
+	carbonHome := filepath.Join(updateRoot, constant.CARBON_HOME)
+	if _, err := os.Stat(carbonHome); os.IsNotExist(err) {
+		return findings, nil
+	}
+	err := filepath.Walk(carbonHome, func(absolutePath string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fileInfo.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(updateRoot, absolutePath)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(absolutePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		fileFindings, err := ScanReaderForSecrets(filepath.ToSlash(relativePath), file)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	return findings, err
+}
+
+// This function checks whether the given file should be scanned for secrets. Other files are only checked whether
+// they are keystores.
+func IsSecretScanCandidate(fileName string) bool {
+	extension := strings.ToLower(path.Ext(fileName))
+	return IsTextFile(fileName) || IsStringIsInSlice(extension, SecretScanExtensions) ||
+		IsStringIsInSlice(extension, KeystoreExtensions)
+}
+
+// This function will scan the content of the file in the given path, read from the given reader, for secrets. If the
+// file is not a secret scan candidate, only its header is read to check whether it is a keystore, so that keystores
+// are detected regardless of their extension.
+func ScanReaderForSecrets(filePath string, reader io.Reader) ([]SecretFinding, error) {
+	if IsSecretScanCandidate(path.Base(filePath)) {
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		return ScanForSecrets(filePath, data), nil
+	}
+	header := make([]byte, keystoreHeaderSize)
+	length, err := io.ReadFull(reader, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if isKeystoreHeader(header[:length]) {
+		return []SecretFinding{{FilePath: filePath, Rule: constant.SECRET_RULE_KEYSTORE}}, nil
+	}
+	return []SecretFinding{}, nil
+}
+
+// This function will scan the given content of the file in the given path for private keys, keystores, AWS keys and
+// passwords.
+func ScanForSecrets(filePath string, data []byte) []SecretFinding {
+	findings := make([]SecretFinding, 0)
+	if isKeystore(filePath, data) {
+		return append(findings, SecretFinding{FilePath: filePath, Rule: constant.SECRET_RULE_KEYSTORE})
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		addFinding := func(rule string) {
+			findings = append(findings, SecretFinding{FilePath: filePath, LineNumber: lineNumber, Rule: rule})
+		}
+		switch {
+		case privateKeyPattern.MatchString(line):
+			addFinding(constant.SECRET_RULE_PRIVATE_KEY)
+		case awsSecretKeyPattern.MatchString(line):
+			addFinding(constant.SECRET_RULE_AWS_SECRET_KEY)
+		case awsAccessKeyPattern.MatchString(line):
+			addFinding(constant.SECRET_RULE_AWS_ACCESS_KEY)
+		case containsPassword(line):
+			addFinding(constant.SECRET_RULE_PASSWORD)
+		}
+	}
+	return findings
+}
+
+// This function checks whether the given file is a keystore, either by the extension or by the header.
+func isKeystore(filePath string, data []byte) bool {
+	return IsStringIsInSlice(strings.ToLower(path.Ext(filePath)), KeystoreExtensions) || isKeystoreHeader(data)
+}
+
+// This function checks whether the given header of a file is the header of a JKS, JCEKS or PKCS12 keystore.
+func isKeystoreHeader(header []byte) bool {
+	for _, magicNumber := range jksMagicNumbers {
+		if bytes.HasPrefix(header, magicNumber) {
+			return true
+		}
+	}
+	return isPKCS12Header(header)
+}
+
+// This function checks whether the given header is the header of a DER encoded PKCS12 PFX, which is a sequence
+// starting with the version 3 followed by the authSafe content info.
+func isPKCS12Header(header []byte) bool {
+	offset, found := skipASN1Header(header, 0, asn1SequenceTag)
+	if !found || !bytes.HasPrefix(header[offset:], pkcs12Version) {
+		return false
+	}
+	offset, found = skipASN1Header(header, offset+len(pkcs12Version), asn1SequenceTag)
+	return found && bytes.HasPrefix(header[offset:], pkcs7OIDPrefix)
+}
+
+// This function returns the offset of the content of the ASN.1 value at the given offset if it has the given tag.
+func skipASN1Header(data []byte, offset int, tag byte) (int, bool) {
+	if len(data) < offset+2 || data[offset] != tag {
+		return 0, false
+	}
+	// Short form or the indefinite form of the length
+	if data[offset+1] <= 0x80 {
+		return offset + 2, true
+	}
+	lengthBytes := int(data[offset+1] & 0x7F)
+	if lengthBytes > 4 || len(data) < offset+2+lengthBytes {
+		return 0, false
+	}
+	return offset + 2 + lengthBytes, true
+}
+
+// This function checks whether the given line assigns a value to a password. Empty values and placeholders such as
+// ${...} and $secret{...} which are resolved at runtime are ignored.
+func containsPassword(line string) bool {
+	for _, match := range passwordPattern.FindAllStringSubmatch(line, -1) {
+		for _, index := range passwordValueIndexes {
+			if isPasswordValue(match[index]) {
+				return true
+			}
+		}
+	}
+	for _, match := range xmlPasswordPattern.FindAllStringSubmatch(line, -1) {
+		if isPasswordValue(match[2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// This function checks whether the given value looks like an actual password.
+func isPasswordValue(value string) bool {
+	value = strings.TrimSpace(value)
+	return len(value) > 0 && !strings.HasPrefix(value, "$")
+}
+
+// This function will read the secrets allowlist in the given location. Each line of the file should contain a path
+// pattern relative to the update root (ie. carbon.home/repository/conf/*.xml) optionally followed by a rule name.
+// Empty lines and lines starting with '#' are ignored.
+func LoadSecretsAllowlist(location string) (*SecretsAllowlist, error) {
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+	allowlist := SecretsAllowlist{}
+	for _, line := range SplitLines(string(data)) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if _, err := path.Match(fields[0], ""); err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid path pattern '%s' found in '%s'. %v", fields[0],
+				location, err))
+		}
+		entry := secretsAllowlistEntry{pathPattern: fields[0]}
+		if len(fields) > 1 {
+			entry.rule = fields[1]
+		}
+		allowlist.entries = append(allowlist.entries, entry)
+	}
+	return &allowlist, nil
+}
+
+// This function checks whether the given finding is allowed by the allowlist.
+func (allowlist *SecretsAllowlist) IsAllowed(finding SecretFinding) bool {
+	if allowlist == nil {
+		return false
+	}
+	for _, entry := range allowlist.entries {
+		if len(entry.rule) > 0 && entry.rule != finding.Rule {
+			continue
+		}
+		if matched, _ := path.Match(entry.pathPattern, finding.FilePath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// This function returns the findings which are not allowed by the allowlist.
+func (allowlist *SecretsAllowlist) Filter(findings []SecretFinding) []SecretFinding {
+	filtered := make([]SecretFinding, 0)
+	for _, finding := range findings {
+		if allowlist.IsAllowed(finding) {
+			logger.Debug(fmt.Sprintf("%s is allowed by the secrets allowlist", finding))
+			continue
+		}
+		filtered = append(filtered, finding)
+	}
+	return filtered
+}
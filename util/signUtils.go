@@ -0,0 +1,162 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/wso2/update-creator-tool/constant"
+	"golang.org/x/crypto/openpgp"
+)
+
+// This function returns the location of the detached signature file of the given file.
+func GetSignatureFilePath(filePath string) string {
+	return filePath + constant.SIGNATURE_FILE_EXTENSION
+}
+
+// This function will sign the file in the given location with the private key in the given key file and write the
+// detached signature next to the file. Ed25519 keys in PKCS#8 PEM format and unencrypted armored PGP keys are
+// supported. The location of the signature file is returned.
+func SignFile(filePath, privateKeyPath string) (string, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	keyData, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return "", err
+	}
+	signature, err := Sign(data, keyData)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("unable to sign '%s' using '%s'. %v", filePath, privateKeyPath, err))
+	}
+	signatureFilePath := GetSignatureFilePath(filePath)
+	if err = ioutil.WriteFile(signatureFilePath, signature, 0644); err != nil {
+		return "", err
+	}
+	return signatureFilePath, nil
+}
+
+// This function will create an armored detached signature of the given data using the given private key.
+func Sign(data, keyData []byte) ([]byte, error) {
+	if bytes.Contains(keyData, []byte(constant.PGP_PRIVATE_KEY_BLOCK)) {
+		return signUsingPGPKey(data, keyData)
+	}
+	block, _ := pem.Decode(keyData)
+	if block == nil || block.Type != constant.PEM_PRIVATE_KEY_BLOCK {
+		return nil, errors.New("unsupported private key. Only Ed25519 keys in PKCS#8 PEM format and armored PGP " +
+			"keys are supported")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("only Ed25519 private keys are supported in PEM format")
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  constant.ED25519_SIGNATURE_BLOCK,
+		Bytes: ed25519.Sign(privateKey, data),
+	}), nil
+}
+
+// This function will create an armored PGP detached signature of the given data.
+func signUsingPGPKey(data, keyData []byte) ([]byte, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keyData))
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			return nil, errors.New("encrypted PGP private keys are not supported")
+		}
+		var signature bytes.Buffer
+		if err = openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(data), nil); err != nil {
+			return nil, err
+		}
+		return signature.Bytes(), nil
+	}
+	return nil, errors.New("private key not found in the PGP key ring")
+}
+
+// This function will verify the detached signature of the file in the given location against the public keys in the
+// given key files. The location of the key file which verified the signature is returned.
+func VerifyFileSignature(filePath, signatureFilePath string, trustedKeyPaths []string) (string, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	signature, err := ioutil.ReadFile(signatureFilePath)
+	if err != nil {
+		return "", err
+	}
+	for _, trustedKeyPath := range trustedKeyPaths {
+		keyData, err := ioutil.ReadFile(trustedKeyPath)
+		if err != nil {
+			return "", errors.New(fmt.Sprintf("unable to read the trusted key '%s'. %v", trustedKeyPath, err))
+		}
+		err = Verify(data, signature, keyData)
+		if err == nil {
+			return trustedKeyPath, nil
+		}
+		logger.Debug(fmt.Sprintf("Signature of '%s' is not verified by '%s': %v", filePath, trustedKeyPath, err))
+	}
+	return "", errors.New(fmt.Sprintf("signature of '%s' could not be verified using the trusted keys", filePath))
+}
+
+// This function will verify the given armored detached signature of the given data using the given public key.
+func Verify(data, signature, keyData []byte) error {
+	if bytes.Contains(signature, []byte(constant.PGP_SIGNATURE_BLOCK)) {
+		if !bytes.Contains(keyData, []byte(constant.PGP_PUBLIC_KEY_BLOCK)) {
+			return errors.New("not a PGP public key")
+		}
+		keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keyData))
+		if err != nil {
+			return err
+		}
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(data), bytes.NewReader(signature))
+		return err
+	}
+	signatureBlock, _ := pem.Decode(signature)
+	if signatureBlock == nil || signatureBlock.Type != constant.ED25519_SIGNATURE_BLOCK {
+		return errors.New("unsupported signature")
+	}
+	keyBlock, _ := pem.Decode(keyData)
+	if keyBlock == nil || keyBlock.Type != constant.PEM_PUBLIC_KEY_BLOCK {
+		return errors.New("not a PEM public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(keyBlock.Bytes)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return errors.New("not an Ed25519 public key")
+	}
+	if !ed25519.Verify(publicKey, data, signatureBlock.Bytes) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
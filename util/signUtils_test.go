@@ -0,0 +1,137 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// This function generates an Ed25519 key pair and returns the PEM encoded private and public keys.
+func createEd25519Keys(t *testing.T) ([]byte, []byte) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while generating the key: %v", err)
+	}
+	privateKeyData, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while marshalling the private key: %v", err)
+	}
+	publicKeyData, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while marshalling the public key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyData}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyData})
+}
+
+// This function generates a PGP key pair and returns the armored private and public keys.
+func createPGPKeys(t *testing.T) ([]byte, []byte) {
+	entity, err := openpgp.NewEntity("wum-uc", "test", "wum-uc@wso2.com", nil)
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while generating the key: %v", err)
+	}
+	var privateKey, publicKey bytes.Buffer
+	writer, _ := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	if err = entity.SerializePrivate(writer, nil); err != nil {
+		t.Fatalf("Test failed, error occurred while serializing the private key: %v", err)
+	}
+	writer.Close()
+	writer, _ = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	if err = entity.Serialize(writer); err != nil {
+		t.Fatalf("Test failed, error occurred while serializing the public key: %v", err)
+	}
+	writer.Close()
+	return privateKey.Bytes(), publicKey.Bytes()
+}
+
+func TestSignAndVerify(t *testing.T) {
+	data := []byte("update zip content")
+	ed25519PrivateKey, ed25519PublicKey := createEd25519Keys(t)
+	pgpPrivateKey, pgpPublicKey := createPGPKeys(t)
+	keyPairs := map[string][][]byte{
+		"Ed25519": {ed25519PrivateKey, ed25519PublicKey},
+		"PGP":     {pgpPrivateKey, pgpPublicKey},
+	}
+	for keyType, keyPair := range keyPairs {
+		signature, err := Sign(data, keyPair[0])
+		if err != nil {
+			t.Fatalf("Test failed, unexpected error while signing using %s key: %v", keyType, err)
+		}
+		if err = Verify(data, signature, keyPair[1]); err != nil {
+			t.Errorf("Test failed, unexpected error while verifying %s signature: %v", keyType, err)
+		}
+		if err = Verify([]byte("tampered content"), signature, keyPair[1]); err == nil {
+			t.Errorf("Test failed, %s signature of tampered content should not be verified", keyType)
+		}
+	}
+	// Signatures should not be verified using other keys
+	signature, _ := Sign(data, ed25519PrivateKey)
+	_, otherPublicKey := createEd25519Keys(t)
+	if err := Verify(data, signature, otherPublicKey); err == nil {
+		t.Errorf("Test failed, signature should not be verified using an untrusted key")
+	}
+	if err := Verify(data, signature, pgpPublicKey); err == nil {
+		t.Errorf("Test failed, Ed25519 signature should not be verified using a PGP key")
+	}
+}
+
+func TestSignAndVerifyFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatalf("Test failed, error occurred while creating temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	privateKey, publicKey := createEd25519Keys(t)
+	_, otherPublicKey := createEd25519Keys(t)
+	privateKeyPath := filepath.Join(tempDir, "private.pem")
+	publicKeyPath := filepath.Join(tempDir, "public.pem")
+	otherPublicKeyPath := filepath.Join(tempDir, "other.pem")
+	updatePath := filepath.Join(tempDir, "WSO2-CARBON-UPDATE-4.4.0-1234.zip")
+	ioutil.WriteFile(privateKeyPath, privateKey, 0600)
+	ioutil.WriteFile(publicKeyPath, publicKey, 0644)
+	ioutil.WriteFile(otherPublicKeyPath, otherPublicKey, 0644)
+	ioutil.WriteFile(updatePath, []byte("update zip content"), 0644)
+
+	signatureFilePath, err := SignFile(updatePath, privateKeyPath)
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if signatureFilePath != updatePath+".sig" {
+		t.Errorf("Test failed, expected: %s, actual: %s", updatePath+".sig", signatureFilePath)
+	}
+	trustedKey, err := VerifyFileSignature(updatePath, signatureFilePath, []string{otherPublicKeyPath,
+		publicKeyPath})
+	if err != nil {
+		t.Fatalf("Test failed, unexpected error: %v", err)
+	}
+	if trustedKey != publicKeyPath {
+		t.Errorf("Test failed, expected: %s, actual: %s", publicKeyPath, trustedKey)
+	}
+	if _, err = VerifyFileSignature(updatePath, signatureFilePath, []string{otherPublicKeyPath}); err == nil {
+		t.Errorf("Test failed, signature should not be verified using an untrusted key")
+	}
+}
@@ -16,8 +16,6 @@ package util
 
 import (
 	"bufio"
-	"crypto/md5"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -67,7 +65,8 @@ type UpdateDescriptorV3 struct {
 	UpdateNumber                string            `yaml:"update_number"`
 	PlatformVersion             string            `yaml:"platform_version"`
 	PlatformName                string            `yaml:"platform_name"`
-	Md5sum                      string            `yaml:"md5sum"`
+	Md5sum                      string            `yaml:"md5sum,omitempty"`
+	Sha256sum                   string            `yaml:"sha256sum,omitempty"`
 	Description                 string            `yaml:"description"`
 	Instructions                string            `yaml:"instructions"`
 	BugFixes                    map[string]string `yaml:"bug_fixes"`
@@ -153,22 +152,6 @@ type JiraResponse struct {
 	Fields Fields `json:"fields"`
 }
 
-// This will return the md5 hash of the file in the given filepath
-func GetMD5(filepath string) (string, error) {
-	var result []byte
-	file, err := os.Open(filepath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	hash := md5.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(hash.Sum(result)), nil
-}
-
 // This function is used to delete the temporary directories
 func CleanUpDirectory(path string) {
 	logger.Debug(fmt.Sprintf("Deleting temporary files: %s", path))
@@ -327,7 +310,7 @@ func ValidateBasicDetailsOfUpdateDescriptorV2(updateDescriptorV2 *UpdateDescript
 	if len(updateDescriptorV2.PlatformName) == 0 {
 		return errors.New("'platform_name' field not found.")
 	}
-	return nil
+	return GetPlatformRegistry().ValidatePlatform(updateDescriptorV2.PlatformName, updateDescriptorV2.PlatformVersion)
 }
 
 func ValidateUpdateDescriptorV2(updateDescriptorV2 *UpdateDescriptorV2) error {
@@ -399,10 +382,18 @@ func ValidateUpdateDescriptorV3(updateDescriptorV3 *UpdateDescriptorV3) error {
 	if len(updateDescriptorV3.PlatformName) == 0 {
 		return errors.New("'platform_name' field not found.")
 	}
+	err = GetPlatformRegistry().ValidatePlatform(updateDescriptorV3.PlatformName, updateDescriptorV3.PlatformVersion)
+	if err != nil {
+		return err
+	}
 
-	// Generate md5sum for the content generated by wum-uc tool
-	md5sum := GenerateMd5sumForGeneratedContent(updateDescriptorV3)
-	if md5sum != updateDescriptorV3.Md5sum {
+	// Generate the checksum for the content generated by wum-uc tool. Old updates only have the md5sum
+	algorithm, expectedChecksum := constant.HASH_ALGORITHM_SHA256, updateDescriptorV3.Sha256sum
+	if len(expectedChecksum) == 0 && len(updateDescriptorV3.Md5sum) != 0 {
+		algorithm, expectedChecksum = constant.HASH_ALGORITHM_MD5, updateDescriptorV3.Md5sum
+	}
+	checksum := GenerateChecksumForGeneratedContent(updateDescriptorV3, algorithm)
+	if checksum != expectedChecksum {
 		HandleErrorAndExit(errors.New("Detected a change in added, " +
 			"modified and removed files in compatible_products/applicable_products sections, " +
 			"please recreate the update zip using `wum-uc create` command"))
@@ -425,13 +416,15 @@ func CopyFile(source string, dest string) (err error) {
 	}
 	defer df.Close()
 	_, err = io.Copy(df, sf)
-	if err == nil {
-		si, err := os.Stat(source)
-		if err != nil {
-			return os.Chmod(dest, si.Mode())
-		}
+	if err != nil {
+		return err
 	}
-	return
+	// Preserve the permissions of the source file, ie. executable bits of scripts
+	si, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	return os.Chmod(dest, si.Mode().Perm())
 }
 
 // Recursively copies a directory tree, attempting to preserve permissions
@@ -559,45 +552,6 @@ func PrintInBold(args ...interface{}) {
 	color.Unset()
 }
 
-// This function will get the Jira summary associated with the given jira id. If an error occur, we just simply ignore
-// the error and return the default response.
-func GetJiraSummary(id string) string {
-	defaultResponse := constant.JIRA_SUMMARY_DEFAULT
-	logger.Debug(fmt.Sprintf("Getting Jira summary for: %s", id))
-	req, err := http.NewRequest("GET", constant.JIRA_API_URL+id, nil)
-	logger.Trace(fmt.Sprintf("Request: %v", req))
-	if err != nil {
-		logger.Debug(fmt.Sprintf("Error occurred while creating a new request: %v", err))
-		return defaultResponse
-	}
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		logger.Debug(fmt.Sprintf("Error occurred while requesting: %v", err))
-		return defaultResponse
-	}
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		logger.Debug(fmt.Sprintf("Error occurred while getting response body: %v", err))
-		return defaultResponse
-	}
-	responseBody := string(body)
-	logger.Debug(fmt.Sprintf("Response body: %v", responseBody))
-
-	jiraResponse := JiraResponse{}
-	err = json.Unmarshal(body, &jiraResponse)
-	if err != nil {
-		logger.Debug(fmt.Sprintf("Error occurred while unmarshalling json. Error: %v", err))
-		return defaultResponse
-	}
-	logger.Debug(fmt.Sprintf("jiraResponse: %v", jiraResponse))
-	if len(jiraResponse.Fields.Summary) > 0 {
-		return jiraResponse.Fields.Summary
-	}
-	logger.Debug("Summary field not found in the jira response")
-	return defaultResponse
-}
-
 // This function will do the following operations on the provided string.
 // 1) Replace \r with \n - Some older files have MAC OS 9 line endings (\r) and this will cause issues when processing
 //    these strings using regular expressions.
@@ -650,49 +604,17 @@ func GetRelativePath(file *zip.File) (relativePath string) {
 
 // Download a file from given url to the given location.
 func DownloadFile(file, url string) error {
-	// Get the data
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	// Check if the response is successful
-	if resp.StatusCode != http.StatusOK {
-		return errors.New(fmt.Sprintf("Could not download the file from: %s", url))
-	}
-	// Create the file
-	out, err := os.Create(file)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-	// Write the body to file
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return err
-	}
-	return nil
+	return GetHTTPClient().Download(url, file)
 }
 
 // Download the content from given url as a byte array.
 func GetContentFromUrl(url string) ([]byte, error) {
-	// Get the data
-	resp, err := http.Get(url)
+	content, err := GetHTTPClient().Get(url)
 	if err != nil {
+		logger.Debug(fmt.Sprintf("Could not download the file from: %s. %v", url, err))
 		return []byte{}, err
 	}
-	defer resp.Body.Close()
-	// Check if the response is successful
-	if resp.StatusCode != http.StatusOK {
-		logger.Debug(fmt.Sprintf("Could not download the file from: %s", url))
-		return []byte{}, errors.New(fmt.Sprintf("Could not download the file from: %s", url))
-	}
-	// Read content
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-	return respBytes, nil
+	return content, nil
 }
 
 func createPartialUpdateFileRequest(updateDescriptorV2 *UpdateDescriptorV2) *PartialUpdateFileRequest {
@@ -714,32 +636,37 @@ func createPartialUpdateFileRequest(updateDescriptorV2 *UpdateDescriptorV2) *Par
 }
 
 // Used for receiving partial updates for the identified file changes
-func GetPartialUpdatedFiles(updateDescriptorV2 *UpdateDescriptorV2) *PartialUpdatedFileResponse {
+func GetPartialUpdatedFiles(updateDescriptorV2 *UpdateDescriptorV2) (*PartialUpdatedFileResponse, error) {
 	// Create partial update request
 	partialUpdateFileRequest := createPartialUpdateFileRequest(updateDescriptorV2)
 	requestBody := new(bytes.Buffer)
 	if err := json.NewEncoder(requestBody).Encode(partialUpdateFileRequest); err != nil {
-		HandleErrorAndExit(err)
+		return nil, err
 	}
 	logger.Debug(fmt.Sprintf("Reqeust sent: %v", requestBody))
 	// Invoke the API
 	apiURL := GetWUMUCConfigs().ServerURL + "/" + constant.FILES_API_CONTEXT + "/" + constant.
 		FILES_API_VERSION + "/" + constant.APPLICABLE_PRODUCTS + "?" + constant.FILE_LIST_ONLY
-	response := InvokePOSTRequest(apiURL, requestBody)
+	response, err := InvokePOSTRequest(apiURL, requestBody)
+	if err != nil {
+		return nil, err
+	}
 	if response.StatusCode != http.StatusOK {
-		HandleUnableToConnectErrorAndExit(nil)
+		return nil, newHTTPError(apiURL, response)
 	}
 	partialUpdatedFileResponse := PartialUpdatedFileResponse{}
 	ProcessResponseFromServer(response, &partialUpdatedFileResponse)
-	return &partialUpdatedFileResponse
+	return &partialUpdatedFileResponse, nil
 }
 
 // Used to invoke POST request with access tokens.
-func InvokePOSTRequest(url string, body io.Reader) *http.Response {
+func InvokePOSTRequest(url string, body io.Reader) (*http.Response, error) {
 	request, err := http.NewRequest(http.MethodPost, url, body)
 	if err != nil {
-		HandleUnableToConnectErrorAndExit(err)
+		return nil, err
 	}
+	// Renew the access token before sending the request, if it is about to expire
+	EnsureValidAccessToken()
 	wumucConfig := GetWUMUCConfigs()
 	request.Header.Add(constant.HEADER_AUTHORIZATION, "Bearer "+wumucConfig.AccessToken)
 	request.Header.Add(constant.HEADER_CONTENT_TYPE, constant.HEADER_VALUE_APPLICATION_JSON)
@@ -747,10 +674,10 @@ func InvokePOSTRequest(url string, body io.Reader) *http.Response {
 }
 
 // Used to invoke GET request with basicAuth
-func InvokeGetRequest(url string) *http.Response {
+func InvokeGetRequest(url string) (*http.Response, error) {
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		HandleUnableToConnectErrorAndExit(err)
+		return nil, err
 	}
 	request.SetBasicAuth(constant.WUMUC_ADMIN_BASIC_AUTH_USERNAME, constant.WUMUC_ADMIN_BASIC_AUTH_PASSWORD)
 	return makeAPICall(request, true)
@@ -765,100 +692,70 @@ func HandleUnableToConnectErrorAndExit(err error) {
 	os.Exit(1)
 }
 
-func makeAPICall(request *http.Request, isBasicAuth bool) *http.Response {
-	var readerCloser1, readerCloser2 io.ReadCloser
-	if !isBasicAuth {
-		// Getting a copy of the request body for use when access token is renewed
-		buf, _ := ioutil.ReadAll(request.Body)
-		readerCloser1 = ioutil.NopCloser(bytes.NewBuffer(buf))
-		readerCloser2 = ioutil.NopCloser(bytes.NewBuffer(buf))
-		// Invoke request
-		request.Body = readerCloser1
+func makeAPICall(request *http.Request, isBasicAuth bool) (*http.Response, error) {
+	httpResponse, err := invokeRequest(request)
+	if err != nil {
+		return nil, err
 	}
 
-	timeout := time.Duration(constant.WUMUC_API_CALL_TIMEOUT * time.Minute)
-	httpResponse := invokeRequest(request, timeout)
-
 	// When authorization is token based and the status codes are 400 or 401 we need to renew the access token
 	if !isBasicAuth && (httpResponse.StatusCode == http.StatusBadRequest || httpResponse.StatusCode == http.
 		StatusUnauthorized) {
+		httpResponse.Body.Close()
 		// Expired access token. Renew the access token and update config.yaml. If the refresh token is
-		// invalid, Authenticate() will notify and exit.
-		Authenticate()
-		// Load the updated config.yaml with new access token
-		wumucConfig := LoadWUMUCConfig(viper.GetString(constant.WUM_UC_HOME))
+		// invalid, renewAccessToken() will notify and exit.
+		renewAccessToken(strings.TrimPrefix(request.Header.Get(constant.HEADER_AUTHORIZATION), "Bearer "))
+		wumucConfig := GetWUMUCConfigs()
 		fmt.Println("Retrying request with renewed Access Token...")
-		// Setting the new access token for backed up request
+		// Setting the new access token for backed up request. Body of the request is buffered by the HTTP client
 		request.Header.Set(constant.HEADER_AUTHORIZATION, "Bearer "+wumucConfig.AccessToken)
-		// Setting the request body backed up
-		request.Body = readerCloser2
-		return invokeRequest(request, timeout)
+		if request.GetBody != nil {
+			if request.Body, err = request.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		return invokeRequest(request)
 	}
-	return httpResponse
+	return httpResponse, nil
 }
 
 // Invoke the client request and handle error scenarios
-func invokeRequest(request *http.Request, timeout time.Duration) *http.Response {
-	response := SendRequest(request, timeout)
-	logger.Debug("Status code %v", response.StatusCode)
-	handleErrorResponses(response)
-	return response
-}
-
-// Send the HTTP request to the server. This does not handle any error scenarios
-func SendRequest(request *http.Request, timeout time.Duration) *http.Response {
-	client := &http.Client{
-		Timeout: timeout,
-	}
-	response, err := client.Do(request)
+func invokeRequest(request *http.Request) (*http.Response, error) {
+	response, err := SendRequest(request)
 	if err != nil {
-		// Here we need to print the exact error to the console. A non-2xx response doesn't cause an error.
-		// This method throws errors when the user doesn't have internet connectivity or there is an issue
-		// with the token URL or for timeout errors.
-		HandleUnableToConnectErrorAndExit(err)
+		return nil, err
 	}
-	return response
+	logger.Debug(fmt.Sprintf("Status code %v", response.StatusCode))
+	if err = handleErrorResponses(request.URL.String(), response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Send the HTTP request to the server using the shared HTTP client. Requests are retried if they fail due to connection
+// errors or with 429 and 5xx status codes. Other error scenarios are not handled.
+func SendRequest(request *http.Request) (*http.Response, error) {
+	return GetHTTPClient().Do(request)
 }
 
 // Handle HTTP Status Codes of the Response
 // Notify and return if 401 or 404
-// Fail and exit if not 200, 201, or 202
-func handleErrorResponses(response *http.Response) {
-	if response.StatusCode == http.StatusTooManyRequests {
-		HandleErrorAndExit(errors.New(constant.TOO_MANY_REQUESTS_ERROR_MSG + constant.CONTINUED_ERROR_REPORT_MSG))
-	}
-
-	if response.StatusCode == http.StatusInternalServerError {
-		HandleUnableToConnectErrorAndExit(nil)
-	}
-
+// Return an error if not 200, 201, or 202
+func handleErrorResponses(url string, response *http.Response) error {
 	if response.StatusCode == http.StatusForbidden {
-		return
+		return nil
 	}
 
 	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusBadRequest {
 		fmt.Println(fmt.Sprintf("wum-uc: %v", constant.INVALID_EXPIRED_REFRESH_TOKEN_MSG))
-		return
-	}
-
-	if response.StatusCode == http.StatusNotFound {
-		fmt.Println("wum-uc: resource not found")
-		errorResponse := ErrorResponse{}
-		ProcessResponseFromServer(response, &errorResponse)
-		HandleErrorAndExit(errors.New(errorResponse.Error.Message))
-	}
-
-	if response.StatusCode == http.StatusConflict {
-		fmt.Println("wum-uc: conflict")
-		errorResponse := ErrorResponse{}
-		ProcessResponseFromServer(response, &errorResponse)
-		HandleErrorAndExit(errors.New(errorResponse.Error.Message))
+		return nil
 	}
 
 	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusCreated &&
 		response.StatusCode != http.StatusAccepted {
-		HandleUnableToConnectErrorAndExit(nil)
+		return newHTTPError(url, response)
 	}
+	return nil
 }
 
 // Get an access token from WSO2 Update with the given username and the password using the
@@ -878,25 +775,11 @@ func GetAccessToken(username string, password []byte, wumucConfig *WUMUCConfig,
 	return InvokeTokenAPI(&payload, wumucConfig, constant.RETRIEVE_ACCESS_TOKEN)
 }
 
-// Renew access token and persist in the config.yaml. Token API sends a new pair of an access token and a refresh token.
+// Renew access token and persist in the credential store. Token API sends a new pair of an access token and a refresh
+// token.
+// Concurrent renewals are serialised.
 func Authenticate() {
-	wumucConfig := GetWUMUCConfigs()
-
-	// Refresh token cannot be empty.
-	if wumucConfig.RefreshToken == "" {
-		HandleErrorAndExit(errors.New(constant.YOU_HAVENT_INITIALIZED_WUMUC_YET_MSG + " " + constant.
-			RUN_WUMUC_INIT_TO_CONTINUE_MSG))
-	}
-
-	tokenResponse, err := RenewAccessToken(wumucConfig)
-	if err != nil {
-		HandleErrorAndExit(err)
-	}
-
-	wumucConfig.RefreshToken = tokenResponse.RefreshToken
-	wumucConfig.AccessToken = tokenResponse.AccessToken
-
-	WriteConfigFile(wumucConfig, wumucConfigFilePath)
+	renewAccessToken(GetWUMUCConfigs().AccessToken)
 }
 
 func RenewAccessToken(wumucConfig *WUMUCConfig) (*TokenResponse, error) {
@@ -908,18 +791,32 @@ func RenewAccessToken(wumucConfig *WUMUCConfig) (*TokenResponse, error) {
 
 }
 
+// Get an access token using the 'client_credentials' grant type of Oauth2. This is used to authenticate without a WSO2
+// account, ie. in CI builds.
+func GetClientCredentialsToken(wumucConfig *WUMUCConfig) (*TokenResponse, error) {
+	payload := url.Values{}
+	payload.Add("grant_type", "client_credentials")
+	fmt.Fprintln(os.Stderr, "Authenticating...")
+	return InvokeTokenAPI(&payload, wumucConfig, constant.CLIENT_CREDENTIALS_TOKEN)
+}
+
 // Invokes the configured token API of the API gateway. This method can be used to get access tokens
-// as well as renew access tokens using the refresh token.
+// as well as renew access tokens using the refresh token. Client credentials are used to authenticate
+// with the token API if they are available, or else the application key of wum-uc.
 func InvokeTokenAPI(payload *url.Values, wumucConfig *WUMUCConfig, tokenType string) (*TokenResponse, error) {
 	request, err := http.NewRequest(http.MethodPost, wumucConfig.TokenURL, bytes.NewBufferString(payload.Encode()))
 	if err != nil {
 		HandleUnableToConnectErrorAndExit(err)
 	}
-	request.Header.Add(constant.HEADER_AUTHORIZATION, "Basic "+wumucConfig.AppKey)
+	request.Header.Add(constant.HEADER_AUTHORIZATION, wumucConfig.getClientAuthorization())
 	request.Header.Add(constant.HEADER_CONTENT_TYPE, constant.HEADER_VALUE_X_WWW_FORM_URLENCODED)
 
-	response := SendRequest(request, time.Duration(constant.WUMUC_UPDATE_TOKEN_TIMEOUT*time.Minute))
-	logger.Debug("Response status code %d\n", response.StatusCode)
+	// Token requests are not retried, as each of them issues new tokens and might invalidate the refresh token
+	response, err := GetHTTPClient().WithoutRetries().Do(request)
+	if err != nil {
+		HandleUnableToConnectErrorAndExit(err)
+	}
+	logger.Debug(fmt.Sprintf("Response status code %d", response.StatusCode))
 
 	tokenResponse := TokenResponse{}
 	if response.StatusCode != http.StatusAccepted && response.StatusCode != http.StatusOK {
@@ -932,6 +829,10 @@ func InvokeTokenAPI(payload *url.Values, wumucConfig *WUMUCConfig, tokenType str
 		} else if constant.RENEW_REFRESH_TOKEN == tokenType && http.StatusBadRequest == response.StatusCode && constant.
 			INVALID_GRANT == tokenErrorResponse.Error {
 			return &tokenResponse, errors.New("Your session has timed out, run 'wum-uc init' to continue")
+		} else if constant.CLIENT_CREDENTIALS_TOKEN == tokenType && (http.StatusBadRequest == response.StatusCode ||
+			http.StatusUnauthorized == response.StatusCode) {
+			return &tokenResponse, errors.New(fmt.Sprintf("Invalid client credentials. %s",
+				tokenErrorResponse.ErrorDescription))
 		} else {
 			HandleUnableToConnectErrorAndExit(errors.New(tokenErrorResponse.Error + ":" + tokenErrorResponse.
 				ErrorDescription))
@@ -966,6 +867,10 @@ func Init(username string, password []byte) {
 
 	// Get WUMUC configurations
 	wumucConfig := GetWUMUCConfigs()
+	// Client credentials used previously are replaced by the user's credentials
+	wumucConfig.ClearTokens()
+	wumucConfig.ClientID = ""
+	wumucConfig.credentialsFromEnvironment = false
 	var tokenResponse *TokenResponse
 	var err error
 	// If user has supplied both username and password by -u and -p flags
@@ -989,9 +894,50 @@ func Init(username string, password []byte) {
 	}
 	WUMUCHomePath := viper.GetString(constant.WUM_UC_HOME)
 	wumucConfig.Username = username
-	wumucConfig.RefreshToken = tokenResponse.RefreshToken
-	wumucConfig.AccessToken = tokenResponse.AccessToken
-	WriteConfigFile(wumucConfig, filepath.Join(WUMUCHomePath, constant.WUMUC_CONFIG_FILE))
+	wumucConfig.SetTokens(tokenResponse)
+	err = WriteConfigFile(wumucConfig, filepath.Join(WUMUCHomePath, constant.WUMUC_CONFIG_FILE))
+	if err != nil {
+		HandleErrorAndExit(err, "Error occurred while storing the access token.")
+	}
+}
+
+// Initialize wum-uc with the given OAuth2 client credentials, so that wum-uc can be used without a WSO2 account. If
+// the client secret is not given, prompt for it. Client secret is kept in the credential store.
+func InitWithClientCredentials(clientID string, clientSecret []byte) {
+	logger.Debug("Initializing wum-uc with client credentials")
+	if len(clientID) == 0 {
+		HandleErrorAndExit(errors.New(fmt.Sprintf("client id is not given. Use the --client-id flag or the %s "+
+			"environment variable", constant.WUMUC_CLIENT_ID)))
+	}
+	if len(clientSecret) == 0 {
+		fmt.Fprintf(os.Stderr, "Client secret for '%v': ", clientID)
+		var err error
+		clientSecret, err = terminal.ReadPassword(int(syscall.Stdin))
+		if err != nil {
+			HandleErrorAndExit(err, constant.UNABLE_TO_READ_YOUR_INPUT_MSG)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	if len(clientSecret) == 0 {
+		HandleErrorAndExit(errors.New("client secret cannot be empty"))
+	}
+
+	wumucConfig := GetWUMUCConfigs()
+	wumucConfig.ClearTokens()
+	wumucConfig.Username = ""
+	wumucConfig.ClientID = clientID
+	wumucConfig.ClientSecret = string(clientSecret)
+	wumucConfig.credentialsFromEnvironment = false
+	tokenResponse, err := GetClientCredentialsToken(wumucConfig)
+	if err != nil {
+		HandleUnableToConnectErrorAndExit(err)
+	}
+	wumucConfig.SetTokens(tokenResponse)
+	WUMUCHomePath := viper.GetString(constant.WUM_UC_HOME)
+	err = WriteConfigFile(wumucConfig, filepath.Join(WUMUCHomePath, constant.WUMUC_CONFIG_FILE))
+	if err != nil {
+		HandleErrorAndExit(err, "Error occurred while writing the client credentials.")
+	}
 }
 
 // Get credentials from the user. Maximum password attempts is 3. If the user specify both the
@@ -1059,9 +1005,9 @@ func getCredentials(username string) (bool, string, []byte) {
 	return true, username, password
 }
 
-// Used to generate the md5sum required in validating the update-descriptor3.yaml for identifying whether the developer
-// has edited beyond what he/she has to edit.
-func GenerateMd5sumForGeneratedContent(updateDescriptorV3 *UpdateDescriptorV3) string {
+// Used to generate the checksum required in validating the update-descriptor3.yaml for identifying whether the
+// developer has edited beyond what he/she has to edit, using the given hash algorithm.
+func GenerateChecksumForGeneratedContent(updateDescriptorV3 *UpdateDescriptorV3, algorithm string) string {
 	var buffer bytes.Buffer
 	var addedFileString string
 	var modifiedFileString string
@@ -1181,7 +1127,11 @@ func GenerateMd5sumForGeneratedContent(updateDescriptorV3 *UpdateDescriptorV3) s
 	buffer.WriteString(updateDescriptorV3.PlatformVersion)
 	buffer.WriteString(updateDescriptorV3.PlatformName)
 
-	return fmt.Sprintf("%x", md5.Sum(buffer.Bytes()))
+	checksum, err := GetChecksumOfData(buffer.Bytes(), algorithm)
+	if err != nil {
+		HandleErrorAndExit(err)
+	}
+	return checksum
 }
 
 // Check whether user has filled requested information after update-descriptor3.yaml is been created
@@ -15,6 +15,9 @@
 package util
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/wso2/update-creator-tool/constant"
@@ -339,3 +342,31 @@ func TestProcessString02(t *testing.T) {
 		t.Errorf("Test failed, expected: '%v', actual: '%v'", expectedResult, result)
 	}
 }
+
+func TestCopyFilePreservesPermissions(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "wum-uc-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	source := filepath.Join(tempDir, "wso2server.sh")
+	if err = ioutil.WriteFile(source, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile is affected by the umask, so set the permissions explicitly
+	if err = os.Chmod(source, 0755); err != nil {
+		t.Fatal(err)
+	}
+	destination := filepath.Join(tempDir, "copy.sh")
+	if err = CopyFile(source, destination); err != nil {
+		t.Fatal(err)
+	}
+	destinationInfo, err := os.Stat(destination)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destinationInfo.Mode().Perm() != 0755 {
+		t.Errorf("Test failed, expected: %v, actual: %v", os.FileMode(0755), destinationInfo.Mode().Perm())
+	}
+}
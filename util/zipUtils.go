@@ -0,0 +1,99 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// Matches paths which start with a Windows drive letter, ie. C:/foo
+var windowsDrivePathPattern = regexp.MustCompile(`^[a-zA-Z]:`)
+
+// This function will check the entries of the given zip file for unsafe entries. Entries with backslashes, absolute
+// paths, '..' segments, duplicate entries, entries which only differ by case and entries with bad CRC32 checksums are
+// rejected. An error listing all such entries is returned.
+func ValidateZipEntries(files []*zip.File) error {
+	problems := make([]string, 0)
+	entryNames := make(map[string]bool)
+	lowerCaseEntryNames := make(map[string]string)
+	for _, file := range files {
+		if problem := getUnsafePathProblem(file.Name); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		if entryNames[file.Name] {
+			problems = append(problems, fmt.Sprintf("'%s' is a duplicate entry", file.Name))
+			continue
+		}
+		entryNames[file.Name] = true
+
+		lowerCaseName := strings.ToLower(strings.TrimSuffix(file.Name, "/"))
+		if existingName, found := lowerCaseEntryNames[lowerCaseName]; found {
+			problems = append(problems, fmt.Sprintf("'%s' collides with '%s' on case insensitive file systems",
+				file.Name, existingName))
+			continue
+		}
+		lowerCaseEntryNames[lowerCaseName] = file.Name
+
+		if err := checkZipEntryCRC(file); err != nil {
+			problems = append(problems, fmt.Sprintf("'%s' is corrupted. %v", file.Name, err))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	message := "Unsafe entries found in the zip file."
+	for _, problem := range problems {
+		message += "\n\t" + problem
+	}
+	return errors.New(message)
+}
+
+// This function returns the reason if the given entry name is not safe to be extracted. Otherwise an empty string is
+// returned.
+func getUnsafePathProblem(name string) string {
+	if strings.Contains(name, "\\") {
+		return fmt.Sprintf("'%s' contains backslashes", name)
+	}
+	if strings.HasPrefix(name, "/") || windowsDrivePathPattern.MatchString(name) {
+		return fmt.Sprintf("'%s' is an absolute path", name)
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return fmt.Sprintf("'%s' contains '..'", name)
+		}
+	}
+	return ""
+}
+
+// This function will read the given zip entry completely so that the CRC32 checksum of the content is verified.
+func checkZipEntryCRC(file *zip.File) error {
+	if file.FileInfo().IsDir() {
+		return nil
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = io.Copy(ioutil.Discard, reader)
+	return err
+}
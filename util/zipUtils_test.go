@@ -0,0 +1,138 @@
+// Copyright (c) 2018, WSO2 Inc. (http://www.wso2.org) All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+	// If set, this value is written as the CRC32 checksum of the entry instead of the actual checksum
+	crc32 uint32
+}
+
+// This function will create a zip with the given entries without any validation of the entry names, and return the
+// entries of it.
+func createZipWithEntries(t *testing.T, entries []zipEntry) []*zip.File {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		var compressed bytes.Buffer
+		compressor, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressor.Write([]byte(entry.content))
+		compressor.Close()
+
+		checksum := crc32.ChecksumIEEE([]byte(entry.content))
+		if entry.crc32 != 0 {
+			checksum = entry.crc32
+		}
+		header := &zip.FileHeader{
+			Name:               entry.name,
+			Method:             zip.Deflate,
+			CRC32:              checksum,
+			CompressedSize64:   uint64(compressed.Len()),
+			UncompressedSize64: uint64(len(entry.content)),
+		}
+		rawWriter, err := writer.CreateRaw(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawWriter.Write(compressed.Bytes())
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Insecure paths are reported by the reader depending on the GODEBUG settings, but the reader is still usable
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if reader == nil {
+		t.Fatal(err)
+	}
+	return reader.File
+}
+
+func TestValidateZipEntriesValid(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/"},
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/LICENSE.txt", content: "license"},
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/repository/components/plugins/a..b.jar", content: "a"},
+	})
+	if err := ValidateZipEntries(files); err != nil {
+		t.Errorf("Test failed, unexpected error: %v", err)
+	}
+}
+
+func TestValidateZipEntriesPathTraversal(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/../../etc/x", content: "x"},
+	})
+	checkZipEntriesError(t, files, "contains '..'")
+}
+
+func TestValidateZipEntriesAbsolutePath(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{{name: "/etc/x", content: "x"}})
+	checkZipEntriesError(t, files, "is an absolute path")
+
+	files = createZipWithEntries(t, []zipEntry{{name: "C:/Windows/x", content: "x"}})
+	checkZipEntriesError(t, files, "is an absolute path")
+}
+
+func TestValidateZipEntriesBackslash(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234\\carbon.home\\x", content: "x"},
+	})
+	checkZipEntriesError(t, files, "contains backslashes")
+}
+
+func TestValidateZipEntriesDuplicates(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/x", content: "x"},
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/x", content: "y"},
+	})
+	checkZipEntriesError(t, files, "is a duplicate entry")
+}
+
+func TestValidateZipEntriesCaseCollision(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/lib/A.jar", content: "x"},
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/lib/a.jar", content: "y"},
+	})
+	checkZipEntriesError(t, files, "collides with")
+}
+
+func TestValidateZipEntriesBadCRC(t *testing.T) {
+	files := createZipWithEntries(t, []zipEntry{
+		{name: "WSO2-CARBON-UPDATE-4.4.0-1234/carbon.home/x", content: "x", crc32: 1234},
+	})
+	checkZipEntriesError(t, files, "is corrupted")
+}
+
+func checkZipEntriesError(t *testing.T, files []*zip.File, expected string) {
+	err := ValidateZipEntries(files)
+	if err == nil {
+		t.Errorf("Test failed, expected an error containing: %s", expected)
+	} else if !strings.Contains(err.Error(), expected) {
+		t.Errorf("Test failed, expected an error containing: %s, actual: %v", expected, err)
+	}
+}